@@ -0,0 +1,86 @@
+package mysql
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// auditLog appends a JSON line per executed statement to a file.
+type auditLog struct {
+	path string
+	mu   sync.Mutex
+}
+
+type auditEntry struct {
+	Time         time.Time `json:"time"`
+	ResourceType string    `json:"resource_type,omitempty"`
+	ResourceID   string    `json:"resource_id,omitempty"`
+	Operation    string    `json:"operation,omitempty"`
+	Statement    string    `json:"statement"`
+	DurationMs   float64   `json:"duration_ms"`
+	RowsAffected *int64    `json:"rows_affected,omitempty"`
+	ErrorCode    uint16    `json:"error_code,omitempty"`
+	Error        string    `json:"error,omitempty"`
+	DryRun       bool      `json:"dry_run,omitempty"`
+}
+
+func newAuditLog(path string) (*auditLog, error) {
+	// Make sure the file can be written before any statement is run.
+	f, err := os.OpenFile(expandHome(path), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed opening audit log: %w", err)
+	}
+	f.Close()
+
+	return &auditLog{path: expandHome(path)}, nil
+}
+
+func newAuditEntry(query string, duration time.Duration, result sql.Result, err error) auditEntry {
+	entry := auditEntry{
+		Time:       time.Now().UTC(),
+		Statement:  redactSQL(query),
+		DurationMs: float64(duration.Microseconds()) / 1000,
+	}
+	if result != nil && err == nil {
+		if rows, rowsErr := result.RowsAffected(); rowsErr == nil {
+			entry.RowsAffected = &rows
+		}
+	}
+	if err != nil {
+		entry.ErrorCode = mysqlErrorNumber(err)
+		entry.Error = redactSQL(err.Error())
+	}
+	return entry
+}
+
+func (l *auditLog) write(entries []auditEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	var lines []byte
+	for _, entry := range entries {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		lines = append(append(lines, line...), '\n')
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(lines); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
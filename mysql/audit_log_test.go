@@ -0,0 +1,114 @@
+package mysql
+
+import (
+	"bufio"
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func readAuditEntries(t *testing.T, path string) []auditEntry {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed opening audit log: %v", err)
+	}
+	defer f.Close()
+
+	var entries []auditEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry auditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("audit log line is not JSON: %v", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestWrapResourceFunc_AttributesStatements(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	auditLog, err := newAuditLog(path)
+	if err != nil {
+		t.Fatalf("failed creating audit log: %v", err)
+	}
+
+	db := newUnreachableDB(t)
+
+	create := wrapResourceFunc("mysql_user", "create", func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		executor := newStatementExecutor(ctx, db, statementOptionsFromMeta(meta))
+		if _, err := executor.ExecContext(ctx, "CREATE USER 'jdoe'@'%' IDENTIFIED BY 'secret'"); err == nil {
+			t.Error("expected statement to fail")
+		}
+		d.SetId("jdoe@%")
+		return nil
+	})
+
+	d := schema.TestResourceDataRaw(t, map[string]*schema.Schema{}, map[string]interface{}{})
+	if diags := create(context.Background(), d, &MySQLConfiguration{StatementOptions: StatementOptions{AuditLog: auditLog}}); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	entries := readAuditEntries(t, path)
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 audit log entry, got %d", len(entries))
+	}
+	entry := entries[0]
+	if entry.ResourceType != "mysql_user" || entry.ResourceID != "jdoe@%" || entry.Operation != "create" {
+		t.Errorf("expected entry to be attributed to the created user, got %+v", entry)
+	}
+	if entry.Statement != "CREATE USER 'jdoe'@'%' IDENTIFIED BY <SENSITIVE>" {
+		t.Errorf("expected redacted statement, got %s", entry.Statement)
+	}
+	if entry.Error == "" {
+		t.Error("expected failed statement to record its error")
+	}
+}
+
+func TestWrapResourceFunc_NoAuditLog(t *testing.T) {
+	called := false
+	read := wrapResourceFunc("mysql_user", "read", func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		called = true
+		if resourceOperationFromContext(ctx) != nil {
+			t.Error("expected no resource operation without audit_log_path or dry_run")
+		}
+		return nil
+	})
+
+	d := schema.TestResourceDataRaw(t, map[string]*schema.Schema{}, map[string]interface{}{})
+	read(context.Background(), d, &MySQLConfiguration{})
+	if !called {
+		t.Error("expected wrapped function to be called")
+	}
+}
+
+func TestNewAuditEntry(t *testing.T) {
+	entry := newAuditEntry("DROP DATABASE `test`", 1500*time.Microsecond, driver.RowsAffected(3), nil)
+	if entry.RowsAffected == nil || *entry.RowsAffected != 3 {
+		t.Errorf("expected 3 affected rows, got %v", entry.RowsAffected)
+	}
+	if entry.DurationMs != 1.5 {
+		t.Errorf("expected duration of 1.5ms, got %v", entry.DurationMs)
+	}
+
+	entry = newAuditEntry("DROP DATABASE `test`", 0, nil, &mysql.MySQLError{Number: 1213, Message: "Deadlock found"})
+	if entry.ErrorCode != 1213 || !strings.Contains(entry.Error, "Deadlock") {
+		t.Errorf("expected error code 1213 to be recorded, got %+v", entry)
+	}
+
+	entry = newAuditEntry("SELECT 1", 0, nil, errors.New("connection refused"))
+	if entry.ErrorCode != 0 || entry.Error != "connection refused" {
+		t.Errorf("expected non-MySQL error to be recorded without code, got %+v", entry)
+	}
+}
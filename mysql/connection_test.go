@@ -2,12 +2,20 @@ package mysql
 
 import (
 	"context"
+	"crypto/tls"
 	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/go-sql-driver/mysql"
 	"github.com/hashicorp/go-version"
+	rds "github.com/krotscheck/go-rds-driver"
 )
 
 func TestSetSQLModeParam_MySQL56(t *testing.T) {
@@ -192,10 +200,24 @@ func TestConnectionCache_Initialized(t *testing.T) {
 	connectionCacheMtx.Unlock()
 }
 
+func TestDbConnection_CheckOnce(t *testing.T) {
+	conn := &DbConnection{}
+	executor := &StatementExecutor{connection: conn}
+	if !executor.checkOnce("event_scheduler") {
+		t.Error("expected the first check to run")
+	}
+	if conn.checkOnce("event_scheduler") {
+		t.Error("expected the check to run once per connection")
+	}
+	if !(&StatementExecutor{}).checkOnce("event_scheduler") {
+		t.Error("expected executors without a connection to run every check")
+	}
+}
+
 func TestConnectionCache_StoreAndRetrieve(t *testing.T) {
 	v, _ := version.NewVersion("8.0.0")
 	testConn := &DbConnection{
-		Version: v,
+		ServerInfo: &ServerInfo{Version: v},
 	}
 
 	connectionCacheMtx.Lock()
@@ -215,7 +237,7 @@ func TestConnectionCache_StoreAndRetrieve(t *testing.T) {
 func TestConnectionCache_CacheHit(t *testing.T) {
 	v, _ := version.NewVersion("5.7.42")
 	cachedConn := &DbConnection{
-		Version: v,
+		ServerInfo: &ServerInfo{Version: v},
 	}
 
 	conf := &MySQLConfiguration{
@@ -250,8 +272,8 @@ func TestConnectionCache_CacheHit(t *testing.T) {
 func TestConnectionCache_DifferentDSNs(t *testing.T) {
 	v1, _ := version.NewVersion("5.7.42")
 	v2, _ := version.NewVersion("8.0.35")
-	conn1 := &DbConnection{Version: v1}
-	conn2 := &DbConnection{Version: v2}
+	conn1 := &DbConnection{ServerInfo: &ServerInfo{Version: v1}}
+	conn2 := &DbConnection{ServerInfo: &ServerInfo{Version: v2}}
 
 	conf1 := &MySQLConfiguration{
 		Config: &mysql.Config{
@@ -281,3 +303,398 @@ func TestConnectionCache_DifferentDSNs(t *testing.T) {
 	delete(connectionCache, dsn2)
 	connectionCacheMtx.Unlock()
 }
+
+func TestConnectionCache_RDSDataAPI(t *testing.T) {
+	v, _ := version.NewVersion("8.0.28")
+	cachedConn := &DbConnection{
+		Db:         &sql.DB{},
+		ServerInfo: &ServerInfo{Version: v},
+	}
+
+	conf := &RDSDataAPIConfiguration{
+		Config: &rds.Config{
+			ResourceArn: "arn:aws:rds:us-east-1:123456789012:cluster:test",
+			SecretArn:   "arn:aws:secretsmanager:us-east-1:123456789012:secret:test",
+			AWSRegion:   "us-east-1",
+		},
+	}
+	mysqlConf := &MySQLConfiguration{Config: &mysql.Config{Net: "tcp", Addr: "localhost:3306"}}
+	if conf.connectionCacheKey() == mysqlConf.connectionCacheKey() {
+		t.Fatal("expected Data API cache key to differ from MySQL DSNs")
+	}
+
+	connectionCacheMtx.Lock()
+	connectionCache[conf.connectionCacheKey()] = cachedConn
+	connectionCacheMtx.Unlock()
+	defer func() {
+		connectionCacheMtx.Lock()
+		delete(connectionCache, conf.connectionCacheKey())
+		connectionCacheMtx.Unlock()
+	}()
+
+	for i := 0; i < 2; i++ {
+		db, err := getDatabaseFromMeta(context.Background(), conf)
+		if err != nil {
+			t.Fatalf("unexpected error on cache hit: %v", err)
+		}
+		if db.DB != cachedConn.Db {
+			t.Error("expected cached Data API connection to be reused")
+		}
+		if db.Server != cachedConn.ServerInfo {
+			t.Error("expected cached server info to be reused")
+		}
+	}
+
+	ver, err := getVersionFromMeta(context.Background(), conf)
+	if err != nil {
+		t.Fatalf("unexpected error getting version: %v", err)
+	}
+	if ver != v {
+		t.Error("expected version to come from the cached connection")
+	}
+}
+
+func TestNewConnector_RefreshesAuthTokenOnEveryConnect(t *testing.T) {
+	calls := 0
+	conf := &MySQLConfiguration{
+		Config: &mysql.Config{
+			User: "iamuser", Net: "tcp", Addr: "127.0.0.1:1",
+		},
+		AuthToken: func(ctx context.Context) (string, error) {
+			calls++
+			return fmt.Sprintf("token-%d", calls), nil
+		},
+	}
+
+	connector, err := newConnector(conf)
+	if err != nil {
+		t.Fatalf("failed to create connector: %v", err)
+	}
+
+	// Nothing listens on port 1, so both dials fail; the token must still be
+	// requested before each of them.
+	for i := 0; i < 2; i++ {
+		if _, err := connector.Connect(context.Background()); err == nil {
+			t.Fatal("expected dial to fail")
+		}
+	}
+	if calls != 2 {
+		t.Errorf("expected auth token to be requested for every connection, got %d calls", calls)
+	}
+}
+
+func TestNewConnector_AuthTokenError(t *testing.T) {
+	conf := &MySQLConfiguration{
+		Config: &mysql.Config{
+			User: "iamuser", Net: "tcp", Addr: "127.0.0.1:1",
+		},
+		AuthToken: func(ctx context.Context) (string, error) {
+			return "", errors.New("credentials expired")
+		},
+	}
+
+	connector, err := newConnector(conf)
+	if err != nil {
+		t.Fatalf("failed to create connector: %v", err)
+	}
+
+	_, err = connector.Connect(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "credentials expired") {
+		t.Errorf("expected auth token error to be returned, got %v", err)
+	}
+}
+
+type countingDialer struct {
+	calls int
+}
+
+func (d *countingDialer) Dial(network, addr string) (net.Conn, error) {
+	d.calls++
+	return nil, errors.New("dial refused")
+}
+
+func TestNewConnector_UsesOwnDialer(t *testing.T) {
+	dialer1 := &countingDialer{}
+	dialer2 := &countingDialer{}
+	conf1 := &MySQLConfiguration{
+		Config: &mysql.Config{User: "user", Net: "tcp", Addr: "db1:3306"},
+		Dialer: dialer1,
+	}
+	conf2 := &MySQLConfiguration{
+		Config: &mysql.Config{User: "user", Net: "tcp", Addr: "db2:3306"},
+		Dialer: dialer2,
+	}
+
+	for _, conf := range []*MySQLConfiguration{conf1, conf2, conf1} {
+		connector, err := newConnector(conf)
+		if err != nil {
+			t.Fatalf("failed to create connector: %v", err)
+		}
+		if _, err := connector.Connect(context.Background()); err == nil {
+			t.Fatal("expected dial to fail")
+		}
+	}
+
+	if dialer1.calls != 2 || dialer2.calls != 1 {
+		t.Errorf("expected each connector to use its own dialer, got %d and %d calls", dialer1.calls, dialer2.calls)
+	}
+}
+
+func TestNewConnector_UnregisteredTLSConfig(t *testing.T) {
+	conf := &MySQLConfiguration{
+		Config: &mysql.Config{
+			User: "user", Net: "tcp", Addr: "127.0.0.1:1",
+			TLSConfig: "not-registered",
+			TLS:       &tls.Config{ServerName: "db.example.com"},
+		},
+	}
+
+	if _, err := newConnector(conf); err != nil {
+		t.Fatalf("expected TLS config to be used without registering it, got: %v", err)
+	}
+	if conf.Config.TLSConfig != "not-registered" {
+		t.Errorf("expected configuration to be left untouched, got TLSConfig %q", conf.Config.TLSConfig)
+	}
+}
+
+func TestConnectionCacheKey_TransportKey(t *testing.T) {
+	newConf := func(transportKey string) *MySQLConfiguration {
+		return &MySQLConfiguration{
+			Config:       &mysql.Config{User: "user", Net: "tcp", Addr: "localhost:3306"},
+			TransportKey: transportKey,
+		}
+	}
+
+	if key := newConf("").connectionCacheKey(); key != newConf("").Config.FormatDSN() {
+		t.Errorf("expected cache key to be the DSN without transport key, got %s", key)
+	}
+	if newConf("direct").connectionCacheKey() == newConf("socks").connectionCacheKey() {
+		t.Error("expected providers reaching the server differently to use different cache keys")
+	}
+}
+
+func TestGetVersionFromMeta_ConnectionError(t *testing.T) {
+	conf := &MySQLConfiguration{
+		Config: &mysql.Config{
+			User: "user", Net: "tcp", Addr: "127.0.0.1:1",
+		},
+		ConnectRetryTimeoutSec: time.Millisecond,
+	}
+
+	if _, err := getVersionFromMeta(context.Background(), conf); err == nil {
+		t.Error("expected connection error to be returned")
+	}
+	// Callers running at plan time must get the error, not a panic.
+	if err := checkRetainCurrentPasswordSupport(context.Background(), conf); err == nil {
+		t.Error("expected connection error to be returned from checkRetainCurrentPasswordSupport")
+	}
+}
+
+func TestGetVersionFromMeta_UnexpectedConfiguration(t *testing.T) {
+	if _, err := getVersionFromMeta(context.Background(), "not a configuration"); err == nil {
+		t.Error("expected error for unexpected configuration type")
+	}
+}
+
+type fakeConnector struct {
+	err      error
+	readOnly string
+	calls    int
+	execErr  error
+	executed []string
+}
+
+func (c *fakeConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	c.calls++
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &fakeConn{connector: c, readOnly: c.readOnly}, nil
+}
+
+func (c *fakeConnector) Driver() driver.Driver {
+	return nil
+}
+
+type fakeConn struct {
+	driver.Conn
+	connector *fakeConnector
+	readOnly  string
+	closed    bool
+}
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.connector.executed = append(c.connector.executed, query)
+	return driver.RowsAffected(0), c.connector.execErr
+}
+
+func (c *fakeConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+func (c *fakeConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	c.connector.executed = append(c.connector.executed, "BEGIN")
+	return &fakeTx{connector: c.connector}, nil
+}
+
+type fakeTx struct {
+	connector *fakeConnector
+}
+
+func (tx *fakeTx) Commit() error {
+	tx.connector.executed = append(tx.connector.executed, "COMMIT")
+	return nil
+}
+
+func (tx *fakeTx) Rollback() error {
+	tx.connector.executed = append(tx.connector.executed, "ROLLBACK")
+	return nil
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return &fakeRows{values: []driver.Value{[]byte(c.readOnly)}}, nil
+}
+
+type fakeRows struct {
+	values []driver.Value
+	done   bool
+}
+
+func (r *fakeRows) Columns() []string { return []string{"read_only"} }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	copy(dest, r.values)
+	return nil
+}
+
+func TestFailoverConnector_FailsOver(t *testing.T) {
+	db1 := &fakeConnector{err: errors.New("connection refused")}
+	db2 := &fakeConnector{readOnly: "0"}
+	c := &failoverConnector{
+		endpoints:  []string{"db1:3306", "db2:3306"},
+		connectors: []driver.Connector{db1, db2},
+	}
+
+	if _, err := c.Connect(context.Background()); err != nil {
+		t.Fatalf("expected failover to second endpoint, got: %v", err)
+	}
+	if _, err := c.Connect(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if db1.calls != 1 || db2.calls != 2 {
+		t.Errorf("expected to stay on the endpoint failed over to, got %d and %d calls", db1.calls, db2.calls)
+	}
+
+	db2.err = errors.New("connection refused")
+	_, err := c.Connect(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "db1:3306") || !strings.Contains(err.Error(), "db2:3306") {
+		t.Errorf("expected error naming every endpoint, got: %v", err)
+	}
+}
+
+func TestFailoverConnector_RequirePrimary(t *testing.T) {
+	replica := &fakeConnector{readOnly: "1"}
+	primary := &fakeConnector{readOnly: "0"}
+	c := &failoverConnector{
+		endpoints:      []string{"replica:3306", "primary:3306"},
+		connectors:     []driver.Connector{replica, primary},
+		requirePrimary: true,
+	}
+
+	conn, err := c.Connect(context.Background())
+	if err != nil {
+		t.Fatalf("expected to connect to the primary, got: %v", err)
+	}
+	if conn.(*fakeConn).readOnly != "0" {
+		t.Error("expected connection to the writable server")
+	}
+	if c.current != 1 {
+		t.Errorf("expected primary to become the current endpoint, got %d", c.current)
+	}
+}
+
+func TestNewConnector_Endpoints(t *testing.T) {
+	dialer := &recordingDialer{}
+	conf := &MySQLConfiguration{
+		Config:    &mysql.Config{User: "user", Net: "tcp", Addr: "db1:3306"},
+		Dialer:    dialer,
+		Endpoints: []string{"db1:3306", "db2:3306", "db3:3306"},
+	}
+
+	connector, err := newConnector(conf)
+	if err != nil {
+		t.Fatalf("failed to create connector: %v", err)
+	}
+	if _, err := connector.Connect(context.Background()); err == nil {
+		t.Fatal("expected dial to fail")
+	}
+
+	if strings.Join(dialer.addrs, ",") != "db1:3306,db2:3306,db3:3306" {
+		t.Errorf("expected endpoints to be tried in order, got %v", dialer.addrs)
+	}
+	if conf.Config.Addr != "db1:3306" {
+		t.Errorf("expected configuration to be left untouched, got Addr %q", conf.Config.Addr)
+	}
+}
+
+type recordingDialer struct {
+	addrs []string
+}
+
+func (d *recordingDialer) Dial(network, addr string) (net.Conn, error) {
+	d.addrs = append(d.addrs, addr)
+	return nil, errors.New("dial refused")
+}
+
+func TestInitConnector_RunsStatements(t *testing.T) {
+	fake := &fakeConnector{}
+	c := &initConnector{
+		Connector:  fake,
+		statements: []string{"SET SESSION lock_wait_timeout = 5", "SET SESSION sql_log_bin = 0"},
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.Connect(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(fake.executed) != 4 || fake.executed[0] != c.statements[0] || fake.executed[3] != c.statements[1] {
+		t.Errorf("expected init statements to run in order on every connection, got %v", fake.executed)
+	}
+
+	fake.execErr = errors.New("Access denied")
+	conn, err := c.Connect(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "lock_wait_timeout") {
+		t.Errorf("expected error naming the failed statement, got: %v", err)
+	}
+	if conn != nil {
+		t.Error("expected no connection when an init statement fails")
+	}
+}
+
+func TestNewConnector_InitStatements(t *testing.T) {
+	conf := &MySQLConfiguration{
+		Config: &mysql.Config{User: "user", Net: "tcp", Addr: "127.0.0.1:1"},
+	}
+	connector, err := newConnector(conf)
+	if err != nil {
+		t.Fatalf("failed to create connector: %v", err)
+	}
+	if c, ok := connector.(*initConnector); !ok || len(c.statements) != 0 {
+		t.Errorf("expected init hook without statements, got %T", connector)
+	}
+
+	conf.InitStatements = []string{"SET ROLE ALL"}
+	connector, err = newConnector(conf)
+	if err != nil {
+		t.Fatalf("failed to create connector: %v", err)
+	}
+	if _, ok := connector.(*initConnector); !ok {
+		t.Errorf("expected init hook, got %T", connector)
+	}
+}
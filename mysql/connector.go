@@ -0,0 +1,281 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	cloudsql "cloud.google.com/go/cloudsqlconn/mysql/mysql"
+	"github.com/go-sql-driver/mysql"
+	"golang.org/x/net/proxy"
+)
+
+// authTokenFunc returns a short-lived credential to be used as the password
+// of a new connection, e.g. an AWS RDS IAM authentication token.
+type authTokenFunc func(ctx context.Context) (string, error)
+
+// newConnector builds the driver.Connector backing the connection pool of conf.
+// When conf.AuthToken is set, a fresh token is requested every time the pool
+// dials a new connection, so connections opened late in a long run don't
+// authenticate with an expired token.
+//
+// The dialer, TLS config and Cloud SQL dialer are set on the connector rather
+// than registered with the driver, so aliased providers don't share them.
+func newConnector(conf *MySQLConfiguration) (driver.Connector, error) {
+	if len(conf.Endpoints) > 1 || conf.RequirePrimary {
+		return newFailoverConnector(conf)
+	}
+
+	dsnConf := conf.Config.Clone()
+	if dsnConf.TLS != nil {
+		// The TLS config is passed directly, so don't let the driver look
+		// up its name in the global registry.
+		dsnConf.TLSConfig = ""
+	}
+
+	cfg, err := mysql.ParseDSN(dsnConf.FormatDSN())
+	if err != nil {
+		return nil, err
+	}
+
+	if conf.Config.TLS != nil {
+		cfg.TLS = conf.Config.TLS.Clone()
+	}
+
+	if conf.CloudSQLDialer != nil {
+		cloudSQLDialer := conf.CloudSQLDialer
+		cfg.DialFunc = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			conn, err := cloudSQLDialer.Dial(ctx, addr)
+			if err != nil {
+				return nil, err
+			}
+			return &cloudsql.LivenessCheckConn{Conn: conn}, nil
+		}
+	} else if conf.Dialer != nil && cfg.Net == "tcp" {
+		cfg.DialFunc = dialFunc(conf.Dialer)
+	}
+
+	if conf.AuthToken != nil {
+		authToken := conf.AuthToken
+		err = cfg.Apply(mysql.BeforeConnect(func(ctx context.Context, c *mysql.Config) error {
+			token, err := authToken(ctx)
+			if err != nil {
+				return fmt.Errorf("failed getting auth token: %w", err)
+			}
+			c.Passwd = token
+			return nil
+		}))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	connector, err := mysql.NewConnector(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &initConnector{Connector: connector, statements: conf.InitStatements}, nil
+}
+
+// initConnector runs statements on every new connection before the pool
+// hands it out, so session settings apply to all statements of resources.
+// The connections are wrapped in a sessionConn.
+type initConnector struct {
+	driver.Connector
+	statements []string
+}
+
+func (c *initConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(c.statements) > 0 {
+		execer, ok := conn.(driver.ExecerContext)
+		if !ok {
+			conn.Close()
+			return nil, errors.New("connection doesn't support running init statements")
+		}
+		for _, stmt := range c.statements {
+			logSQL(ctx, stmt)
+			if _, err := execer.ExecContext(ctx, stmt, nil); err != nil {
+				conn.Close()
+				return nil, fmt.Errorf("failed running init statement %s: %w", redactSQL(stmt), err)
+			}
+		}
+	}
+
+	if dc, ok := conn.(driverConn); ok {
+		return &sessionConn{driverConn: dc}, nil
+	}
+	return conn, nil
+}
+
+// driverConn is the set of driver interfaces connections of the MySQL driver
+// implement, which sessionConn keeps.
+type driverConn interface {
+	driver.Conn
+	driver.ConnBeginTx
+	driver.ConnPrepareContext
+	driver.ExecerContext
+	driver.QueryerContext
+	driver.Pinger
+	driver.NamedValueChecker
+	driver.SessionResetter
+	driver.Validator
+}
+
+// sessionConn is a pooled connection together with the session settings
+// execWithDeadline read from it, so they're freed with the connection. It is
+// only used while the connection is held, so settings needs no locking.
+type sessionConn struct {
+	driverConn
+	settings *sessionSettings
+}
+
+// dialFunc adapts a proxy.Dialer to the driver's DialFunc.
+func dialFunc(dialer proxy.Dialer) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	if contextDialer, ok := dialer.(proxy.ContextDialer); ok {
+		return contextDialer.DialContext
+	}
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return dialer.Dial(network, addr)
+	}
+}
+
+// failoverConnector connects to the first usable of several endpoints. It
+// starts from the endpoint it last connected to, so once it failed over, new
+// connections keep going to the same server.
+type failoverConnector struct {
+	endpoints      []string
+	connectors     []driver.Connector
+	requirePrimary bool
+
+	mu      sync.Mutex
+	current int
+}
+
+func newFailoverConnector(conf *MySQLConfiguration) (*failoverConnector, error) {
+	endpoints := conf.Endpoints
+	if len(endpoints) == 0 {
+		endpoints = []string{conf.Config.Addr}
+	}
+
+	c := &failoverConnector{
+		endpoints:      endpoints,
+		requirePrimary: conf.RequirePrimary,
+	}
+	for _, endpoint := range endpoints {
+		endpointConf := *conf
+		endpointConf.Config = conf.Config.Clone()
+		endpointConf.Config.Addr = endpoint
+		endpointConf.Endpoints = nil
+		endpointConf.RequirePrimary = false
+
+		connector, err := newConnector(&endpointConf)
+		if err != nil {
+			return nil, fmt.Errorf("endpoint %s: %w", endpoint, err)
+		}
+		c.connectors = append(c.connectors, connector)
+	}
+	return c, nil
+}
+
+func (c *failoverConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	c.mu.Lock()
+	start := c.current
+	c.mu.Unlock()
+
+	var errs []error
+	for i := range c.connectors {
+		idx := (start + i) % len(c.connectors)
+		conn, err := c.connect(ctx, idx)
+		if err != nil {
+			logConnection(ctx, "Failed connecting to endpoint", map[string]interface{}{
+				"endpoint": c.endpoints[idx],
+				"error":    err.Error(),
+			})
+			errs = append(errs, fmt.Errorf("%s: %w", c.endpoints[idx], err))
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+
+		if idx != start {
+			logConnection(ctx, "Failing over to endpoint", map[string]interface{}{
+				"endpoint": c.endpoints[idx],
+				"previous": c.endpoints[start],
+			})
+			c.mu.Lock()
+			c.current = idx
+			c.mu.Unlock()
+		}
+		return conn, nil
+	}
+	return nil, fmt.Errorf("failed connecting to any endpoint: %w", errors.Join(errs...))
+}
+
+func (c *failoverConnector) connect(ctx context.Context, idx int) (driver.Conn, error) {
+	conn, err := c.connectors[idx].Connect(ctx)
+	if err != nil || !c.requirePrimary {
+		return conn, err
+	}
+
+	readOnly, err := isReadOnly(ctx, conn)
+	if err == nil && readOnly {
+		err = errors.New("server is read only")
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+func (c *failoverConnector) Driver() driver.Driver {
+	return c.connectors[0].Driver()
+}
+
+// isReadOnly tells whether conn is connected to a replica or secondary, which
+// don't accept writes. super_read_only can only be enabled together with
+// read_only, so checking the latter covers both.
+func isReadOnly(ctx context.Context, conn driver.Conn) (bool, error) {
+	queryer, ok := conn.(driver.QueryerContext)
+	if !ok {
+		return false, errors.New("connection doesn't support queries")
+	}
+
+	rows, err := queryer.QueryContext(ctx, "SELECT @@GLOBAL.read_only", nil)
+	if err != nil {
+		return false, fmt.Errorf("failed checking read_only: %w", err)
+	}
+	defer rows.Close()
+
+	dest := make([]driver.Value, 1)
+	if err := rows.Next(dest); err != nil {
+		return false, fmt.Errorf("failed checking read_only: %w", err)
+	}
+	switch v := dest[0].(type) {
+	case int64:
+		return v != 0, nil
+	case []byte:
+		return string(v) != "0", nil
+	default:
+		return false, fmt.Errorf("unexpected read_only value %v", v)
+	}
+}
+
+// openDB opens a connection pool for conf without connecting to the server.
+func openDB(conf *MySQLConfiguration) (*sql.DB, error) {
+	connector, err := newConnector(conf)
+	if err != nil {
+		return nil, err
+	}
+	return sql.OpenDB(connector), nil
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	"log"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/id"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -46,7 +45,7 @@ func ShowTables(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 		sql += fmt.Sprintf(" LIKE '%s'", pattern)
 	}
 
-	log.Printf("[DEBUG] SQL: %s", sql)
+	logSQL(ctx, sql)
 
 	rows, err := db.QueryContext(ctx, sql)
 	if err != nil {
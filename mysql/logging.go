@@ -0,0 +1,82 @@
+package mysql
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// logSubsystemSQL logs the statements sent to the server.
+	logSubsystemSQL = "sql"
+	// logSubsystemConnection logs how connections to the server are made.
+	logSubsystemConnection = "connection"
+
+	sensitivePlaceholder = "<SENSITIVE>"
+)
+
+// sensitiveLogFields are masked whenever they're logged as fields.
+var sensitiveLogFields = []string{"password", "auth_string_hashed", "token"}
+
+// sqlStringLiteral matches a single or double quoted SQL string, or a hex
+// literal as used for hashed authentication strings.
+const sqlStringLiteral = `(?:'(?:[^'\\]|\\.|'')*'|"(?:[^"\\]|\\.|"")*"|0x[0-9A-Fa-f]+)`
+
+// sensitiveSQLRegexes match credentials in SQL statements. The first group
+// is kept, the literal following it is redacted.
+var sensitiveSQLRegexes = []*regexp.Regexp{
+	// IDENTIFIED BY 'password', IDENTIFIED BY PASSWORD 'hash',
+	// IDENTIFIED WITH plugin BY|AS 'secret', IDENTIFIED VIA plugin USING 'hash'
+	regexp.MustCompile(`(?i)(\bIDENTIFIED\s+(?:(?:WITH|VIA)\s+\S+\s+)?(?:BY|AS|USING)\s+(?:PASSWORD\s+)?)` + sqlStringLiteral),
+	// PASSWORD('password'), as in SET PASSWORD and MariaDB's USING PASSWORD()
+	regexp.MustCompile(`(?i)(\bPASSWORD\s*\(\s*)` + sqlStringLiteral),
+	// SET PASSWORD [FOR user] = 'hash'
+	regexp.MustCompile(`(?i)(\bSET\s+PASSWORD\s+(?:FOR\s+\S+\s*)?=\s*)` + sqlStringLiteral),
+}
+
+// redactSQL replaces credentials in stmt so it can be logged safely.
+func redactSQL(stmt string) string {
+	for _, re := range sensitiveSQLRegexes {
+		stmt = re.ReplaceAllString(stmt, "${1}"+sensitivePlaceholder)
+	}
+	return stmt
+}
+
+// redactDSN formats conf as a DSN without its password.
+func redactDSN(conf *mysql.Config) string {
+	redacted := conf.Clone()
+	if redacted.Passwd != "" {
+		redacted.Passwd = sensitivePlaceholder
+	}
+	return redacted.FormatDSN()
+}
+
+// withLogSubsystem sets up a provider logging subsystem in ctx. Its level can
+// be set by TF_LOG_PROVIDER_MYSQL_<SUBSYSTEM>.
+func withLogSubsystem(ctx context.Context, subsystem string) context.Context {
+	ctx = tflog.NewSubsystem(ctx, subsystem,
+		// Report the caller of logSQL or logConnection as the location.
+		tflog.WithAdditionalLocationOffset(1),
+		tflog.WithLevelFromEnv("TF_LOG_PROVIDER_MYSQL", subsystem),
+	)
+	ctx = tflog.SubsystemMaskFieldValuesWithFieldKeys(ctx, subsystem, sensitiveLogFields...)
+	ctx = tflog.SubsystemMaskAllFieldValuesRegexes(ctx, subsystem, sensitiveSQLRegexes...)
+	ctx = tflog.SubsystemMaskMessageRegexes(ctx, subsystem, sensitiveSQLRegexes...)
+	return ctx
+}
+
+// logSQL logs a statement about to be sent to the server.
+func logSQL(ctx context.Context, stmt string) {
+	ctx = withLogSubsystem(ctx, logSubsystemSQL)
+	tflog.SubsystemDebug(ctx, logSubsystemSQL, "Executing statement", map[string]interface{}{
+		"statement": redactSQL(stmt),
+	})
+}
+
+// logConnection logs details about connecting to the server.
+func logConnection(ctx context.Context, msg string, additionalFields ...map[string]interface{}) {
+	ctx = withLogSubsystem(ctx, logSubsystemConnection)
+	tflog.SubsystemDebug(ctx, logSubsystemConnection, msg, additionalFields...)
+}
@@ -0,0 +1,51 @@
+package mysql
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestRedactSQL(t *testing.T) {
+	testCases := map[string]string{
+		"CREATE USER 'jdoe'@'%' IDENTIFIED BY 'secret'":                                   "CREATE USER 'jdoe'@'%' IDENTIFIED BY <SENSITIVE>",
+		"ALTER USER 'jdoe'@'%' IDENTIFIED BY 'it''s \\'quoted' RETAIN CURRENT PASSWORD":   "ALTER USER 'jdoe'@'%' IDENTIFIED BY <SENSITIVE> RETAIN CURRENT PASSWORD",
+		"CREATE USER 'jdoe'@'%' identified by password '*ABCDEF'":                         "CREATE USER 'jdoe'@'%' identified by password <SENSITIVE>",
+		"CREATE USER 'jdoe'@'%' IDENTIFIED WITH caching_sha2_password AS '$A$005$xyz'":    "CREATE USER 'jdoe'@'%' IDENTIFIED WITH caching_sha2_password AS <SENSITIVE>",
+		"CREATE USER 'jdoe'@'%' IDENTIFIED WITH caching_sha2_password AS 0x24412430":      "CREATE USER 'jdoe'@'%' IDENTIFIED WITH caching_sha2_password AS <SENSITIVE>",
+		"CREATE USER 'jdoe'@'%' IDENTIFIED VIA ed25519 USING PASSWORD('secret')":          "CREATE USER 'jdoe'@'%' IDENTIFIED VIA ed25519 USING PASSWORD(<SENSITIVE>)",
+		"SET PASSWORD FOR 'jdoe'@'%' = PASSWORD('secret')":                                "SET PASSWORD FOR 'jdoe'@'%' = PASSWORD(<SENSITIVE>)",
+		"SET PASSWORD FOR 'jdoe'@'%' = '*ABCDEF'":                                         "SET PASSWORD FOR 'jdoe'@'%' = <SENSITIVE>",
+		"CREATE AADUSER 'jdoe'@'%' IDENTIFIED BY 'identity'":                              "CREATE AADUSER 'jdoe'@'%' IDENTIFIED BY <SENSITIVE>",
+		"GRANT SELECT ON `db`.* TO 'jdoe'@'%'":                                            "GRANT SELECT ON `db`.* TO 'jdoe'@'%'",
+		"ALTER USER 'jdoe'@'%' PASSWORD EXPIRE INTERVAL 90 DAY":                           "ALTER USER 'jdoe'@'%' PASSWORD EXPIRE INTERVAL 90 DAY",
+		"ALTER USER 'a'@'%' IDENTIFIED BY 'x'; ALTER USER 'b'@'%' IDENTIFIED BY \"y\"":    "ALTER USER 'a'@'%' IDENTIFIED BY <SENSITIVE>; ALTER USER 'b'@'%' IDENTIFIED BY <SENSITIVE>",
+		"CREATE USER 'jdoe'@'%' IDENTIFIED WITH AWSAuthenticationPlugin as 'RDS' REQUIRE": "CREATE USER 'jdoe'@'%' IDENTIFIED WITH AWSAuthenticationPlugin as <SENSITIVE> REQUIRE",
+	}
+	for stmt, expected := range testCases {
+		if redacted := redactSQL(stmt); redacted != expected {
+			t.Errorf("redactSQL(%q): expected %q, got %q", stmt, expected, redacted)
+		}
+	}
+}
+
+func TestRedactDSN(t *testing.T) {
+	conf := &mysql.Config{
+		User:   "iamuser",
+		Passwd: "token-with-secret",
+		Net:    "tcp",
+		Addr:   "db.example.com:3306",
+	}
+
+	dsn := redactDSN(conf)
+	if strings.Contains(dsn, "token-with-secret") {
+		t.Errorf("expected password to be redacted from dsn, got %s", dsn)
+	}
+	if !strings.HasPrefix(dsn, "iamuser:<SENSITIVE>@tcp(db.example.com:3306)") {
+		t.Errorf("expected redacted dsn to keep user and address, got %s", dsn)
+	}
+	if conf.Passwd != "token-with-secret" {
+		t.Error("expected redactDSN to leave the configuration untouched")
+	}
+}
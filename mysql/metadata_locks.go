@@ -0,0 +1,153 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// metadataLockDiagnosisMargin is how long before the deadline of a
+	// statement the sessions blocking it are looked up, while it's still
+	// waiting for its lock.
+	metadataLockDiagnosisMargin = 2 * time.Second
+	metadataLockQueryTimeout    = 5 * time.Second
+)
+
+// metadataLockBlockersQuery finds the sessions holding the metadata locks a
+// session is waiting for.
+const metadataLockBlockersQuery = `SELECT blocking.PROCESSLIST_ID, blocking.PROCESSLIST_USER, blocking.PROCESSLIST_HOST,
+	blocking.PROCESSLIST_COMMAND, blocking.PROCESSLIST_TIME, blocking.PROCESSLIST_INFO,
+	granted.OBJECT_TYPE, granted.OBJECT_SCHEMA, granted.OBJECT_NAME, granted.LOCK_TYPE
+FROM performance_schema.metadata_locks pending
+JOIN performance_schema.threads waiting ON waiting.THREAD_ID = pending.OWNER_THREAD_ID
+JOIN performance_schema.metadata_locks granted ON granted.OBJECT_TYPE = pending.OBJECT_TYPE
+	AND granted.OBJECT_SCHEMA <=> pending.OBJECT_SCHEMA
+	AND granted.OBJECT_NAME <=> pending.OBJECT_NAME
+	AND granted.LOCK_STATUS = 'GRANTED'
+	AND granted.OWNER_THREAD_ID <> pending.OWNER_THREAD_ID
+JOIN performance_schema.threads blocking ON blocking.THREAD_ID = granted.OWNER_THREAD_ID
+WHERE pending.LOCK_STATUS = 'PENDING' AND waiting.PROCESSLIST_ID = ?`
+
+// metadataLockBlocker is a session holding a metadata lock another one waits
+// for.
+type metadataLockBlocker struct {
+	ID           sql.NullInt64
+	User         sql.NullString
+	Host         sql.NullString
+	Command      sql.NullString
+	Time         sql.NullInt64
+	Info         sql.NullString
+	ObjectType   sql.NullString
+	ObjectSchema sql.NullString
+	ObjectName   sql.NullString
+	LockType     sql.NullString
+}
+
+func (b metadataLockBlocker) String() string {
+	object := b.ObjectType.String
+	switch {
+	case b.ObjectSchema.Valid && b.ObjectName.Valid:
+		object += fmt.Sprintf(" %s.%s", quoteIdentifier(b.ObjectSchema.String), quoteIdentifier(b.ObjectName.String))
+	case b.ObjectSchema.Valid:
+		object += " " + quoteIdentifier(b.ObjectSchema.String)
+	case b.ObjectName.Valid:
+		object += " " + quoteIdentifier(b.ObjectName.String)
+	}
+
+	desc := fmt.Sprintf("session %d (%s@%s) holds a %s lock on %s", b.ID.Int64, b.User.String, b.Host.String, b.LockType.String, object)
+	if b.Command.Valid {
+		desc += fmt.Sprintf(", %s for %ds", b.Command.String, b.Time.Int64)
+	}
+	if b.Info.Valid && b.Info.String != "" {
+		desc += ": " + redactSQL(b.Info.String)
+	}
+	return desc
+}
+
+// metadataLockWatch looks up the sessions blocking a statement shortly before
+// its deadline.
+type metadataLockWatch struct {
+	timer *time.Timer
+	done  chan struct{}
+
+	mu       sync.Mutex
+	blockers []metadataLockBlocker
+}
+
+// watchMetadataLocks starts looking for the sessions blocking connectionID
+// shortly before deadline, using db as the connection waiting can't be used.
+func watchMetadataLocks(db *sql.DB, connectionID int64, deadline time.Time) *metadataLockWatch {
+	w := &metadataLockWatch{done: make(chan struct{})}
+
+	wait := time.Until(deadline)
+	if margin := wait / 4; margin < metadataLockDiagnosisMargin {
+		wait -= margin
+	} else {
+		wait -= metadataLockDiagnosisMargin
+	}
+
+	w.timer = time.AfterFunc(wait, func() {
+		defer close(w.done)
+		blockers, err := findMetadataLockBlockers(db, connectionID)
+		if err != nil {
+			logConnection(context.Background(), "Failed looking up metadata lock blockers", map[string]interface{}{"error": err.Error()})
+			return
+		}
+		w.mu.Lock()
+		w.blockers = blockers
+		w.mu.Unlock()
+	})
+	return w
+}
+
+// stop stops the watch and returns the blockers found, if it ran.
+func (w *metadataLockWatch) stop() []metadataLockBlocker {
+	if w.timer.Stop() {
+		return nil
+	}
+	<-w.done
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.blockers
+}
+
+func findMetadataLockBlockers(db *sql.DB, connectionID int64) ([]metadataLockBlocker, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), metadataLockQueryTimeout)
+	defer cancel()
+
+	rows, err := db.QueryContext(ctx, metadataLockBlockersQuery, connectionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var blockers []metadataLockBlocker
+	for rows.Next() {
+		var b metadataLockBlocker
+		err := rows.Scan(&b.ID, &b.User, &b.Host, &b.Command, &b.Time, &b.Info, &b.ObjectType, &b.ObjectSchema, &b.ObjectName, &b.LockType)
+		if err != nil {
+			return nil, err
+		}
+		blockers = append(blockers, b)
+	}
+	return blockers, rows.Err()
+}
+
+// describeMetadataLockBlockers adds the sessions blocking a statement to the
+// error it failed with.
+func describeMetadataLockBlockers(err error, blockers []metadataLockBlocker) error {
+	if len(blockers) == 0 {
+		return err
+	}
+
+	descs := make([]string, len(blockers))
+	for i, b := range blockers {
+		descs[i] = b.String()
+	}
+	return fmt.Errorf("%w; waiting for metadata locks: %s", err, strings.Join(descs, "; "))
+}
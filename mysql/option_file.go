@@ -0,0 +1,244 @@
+package mysql
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+)
+
+// clientOptions are the options of the [client] and login path groups of
+// MySQL option files, keyed by their name with underscores replaced by
+// dashes.
+type clientOptions map[string]string
+
+const (
+	defaultLoginPath = "client"
+
+	// The login path file starts with 4 unused bytes and the 20 byte key
+	// its lines are encrypted with.
+	loginPathFileKeyOffset = 4
+	loginPathFileKeyLen    = 20
+)
+
+// readClientOptions reads the client options from defaultsFile and the login
+// path file, in the order the mysql client does. Later groups and files
+// override the options of earlier ones.
+func readClientOptions(defaultsFile, loginPath string) (clientOptions, error) {
+	groups := []string{defaultLoginPath}
+	if loginPath != "" && loginPath != defaultLoginPath {
+		groups = append(groups, loginPath)
+	}
+
+	opts := clientOptions{}
+	if defaultsFile != "" {
+		f, err := os.Open(expandHome(defaultsFile))
+		if err != nil {
+			return nil, fmt.Errorf("failed reading defaults file: %w", err)
+		}
+		defer f.Close()
+
+		if err := opts.parse(f, groups); err != nil {
+			return nil, fmt.Errorf("failed parsing defaults file %s: %w", defaultsFile, err)
+		}
+	}
+
+	if loginPath != "" {
+		path := loginPathFile()
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed reading login path file: %w", err)
+		}
+		plaintext, err := decryptLoginPathFile(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed decrypting login path file %s: %w", path, err)
+		}
+		if err := opts.parse(bytes.NewReader(plaintext), groups); err != nil {
+			return nil, fmt.Errorf("failed parsing login path file %s: %w", path, err)
+		}
+	}
+
+	return opts, nil
+}
+
+// loginPathFile returns the path of the file mysql_config_editor writes.
+func loginPathFile() string {
+	if path := os.Getenv("MYSQL_TEST_LOGIN_FILE"); path != "" {
+		return path
+	}
+	return expandHome("~/.mylogin.cnf")
+}
+
+// parse adds the options of the given groups in r to opts. !include and
+// !includedir directives are not followed.
+func (opts clientOptions) parse(r io.Reader, groups []string) error {
+	inGroup := false
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' || line[0] == '!' {
+			continue
+		}
+
+		if line[0] == '[' {
+			end := strings.IndexByte(line, ']')
+			if end < 0 {
+				return fmt.Errorf("line %d: unterminated group name", lineNo)
+			}
+			group := strings.TrimSpace(line[1:end])
+			inGroup = false
+			for _, g := range groups {
+				if strings.EqualFold(group, g) {
+					inGroup = true
+				}
+			}
+			continue
+		}
+
+		if !inGroup {
+			continue
+		}
+
+		name, value, _ := strings.Cut(line, "=")
+		name = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), "_", "-")
+		value, err := parseOptionValue(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		opts[name] = value
+	}
+	return scanner.Err()
+}
+
+// parseOptionValue unquotes value and strips trailing comments.
+func parseOptionValue(value string) (string, error) {
+	if value == "" || (value[0] != '"' && value[0] != '\'') {
+		if i := strings.Index(value, " #"); i >= 0 {
+			value = value[:i]
+		}
+		return strings.TrimSpace(unescapeOptionValue(value)), nil
+	}
+
+	quote := value[0]
+	for i := 1; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			i++
+		case quote:
+			return unescapeOptionValue(value[1:i]), nil
+		}
+	}
+	return "", errors.New("unterminated quoted value")
+}
+
+var optionValueUnescaper = strings.NewReplacer(
+	`\b`, "\b",
+	`\t`, "\t",
+	`\n`, "\n",
+	`\r`, "\r",
+	`\s`, " ",
+	`\\`, `\`,
+	`\"`, `"`,
+	`\'`, `'`,
+)
+
+func unescapeOptionValue(value string) string {
+	return optionValueUnescaper.Replace(value)
+}
+
+// decryptLoginPathFile decrypts the file mysql_config_editor writes. Every
+// line is encrypted separately with AES-128-ECB, preceded by its length.
+func decryptLoginPathFile(data []byte) ([]byte, error) {
+	if len(data) < loginPathFileKeyOffset+loginPathFileKeyLen {
+		return nil, errors.New("file is too short")
+	}
+
+	key := make([]byte, aes.BlockSize)
+	for i, b := range data[loginPathFileKeyOffset : loginPathFileKeyOffset+loginPathFileKeyLen] {
+		key[i%aes.BlockSize] ^= b
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var plaintext []byte
+	data = data[loginPathFileKeyOffset+loginPathFileKeyLen:]
+	for len(data) > 0 {
+		if len(data) < 4 {
+			return nil, errors.New("truncated line length")
+		}
+		n := int(binary.LittleEndian.Uint32(data))
+		data = data[4:]
+		if n == 0 || n%aes.BlockSize != 0 || n > len(data) {
+			return nil, fmt.Errorf("invalid encrypted line length %d", n)
+		}
+
+		line := make([]byte, n)
+		for i := 0; i < n; i += aes.BlockSize {
+			block.Decrypt(line[i:i+aes.BlockSize], data[i:i+aes.BlockSize])
+		}
+		data = data[n:]
+
+		padding := int(line[n-1])
+		if padding == 0 || padding > aes.BlockSize {
+			return nil, errors.New("invalid padding")
+		}
+		plaintext = append(plaintext, line[:n-padding]...)
+	}
+	return plaintext, nil
+}
+
+// endpoint returns the address to connect to, or "" if the options don't
+// name a server. Like the mysql client, it uses the socket for localhost.
+func (opts clientOptions) endpoint() string {
+	host := opts["host"]
+	if socket := opts["socket"]; socket != "" && (host == "" || host == "localhost") {
+		return socket
+	}
+	if host == "" {
+		return ""
+	}
+
+	port := opts["port"]
+	if port == "" {
+		port = "3306"
+	}
+	return net.JoinHostPort(host, port)
+}
+
+// tlsConfig returns the tls setting matching ssl-mode, or "" if it isn't set.
+func (opts clientOptions) tlsConfig() string {
+	switch strings.ToUpper(opts["ssl-mode"]) {
+	case "DISABLED":
+		return "false"
+	case "PREFERRED":
+		return "preferred"
+	case "REQUIRED":
+		return "skip-verify"
+	case "VERIFY_CA", "VERIFY_IDENTITY":
+		return "true"
+	default:
+		return ""
+	}
+}
+
+// customTLS returns the certificates set by the ssl-* options, nil if there
+// are none or TLS is disabled.
+func (opts clientOptions) customTLS() *CustomTLS {
+	if opts["ssl-ca"] == "" || opts.tlsConfig() == "false" {
+		return nil
+	}
+	return &CustomTLS{
+		ConfigKey:  "custom",
+		CACert:     expandHome(opts["ssl-ca"]),
+		ClientCert: expandHome(opts["ssl-cert"]),
+		ClientKey:  expandHome(opts["ssl-key"]),
+	}
+}
@@ -0,0 +1,212 @@
+package mysql
+
+import (
+	"context"
+	"crypto/aes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// encryptLoginPathFile encrypts lines like mysql_config_editor does.
+func encryptLoginPathFile(t *testing.T, lines []string) []byte {
+	t.Helper()
+	key := []byte("0123456789abcdefghij")
+	data := append(make([]byte, loginPathFileKeyOffset), key...)
+
+	aesKey := make([]byte, aes.BlockSize)
+	for i, b := range key {
+		aesKey[i%aes.BlockSize] ^= b
+	}
+	block, err := aes.NewCipher(aesKey)
+	if err != nil {
+		t.Fatalf("failed creating cipher: %v", err)
+	}
+
+	for _, line := range lines {
+		plaintext := []byte(line + "\n")
+		padding := aes.BlockSize - len(plaintext)%aes.BlockSize
+		for i := 0; i < padding; i++ {
+			plaintext = append(plaintext, byte(padding))
+		}
+		ciphertext := make([]byte, len(plaintext))
+		for i := 0; i < len(plaintext); i += aes.BlockSize {
+			block.Encrypt(ciphertext[i:i+aes.BlockSize], plaintext[i:i+aes.BlockSize])
+		}
+		data = binary.LittleEndian.AppendUint32(data, uint32(len(ciphertext)))
+		data = append(data, ciphertext...)
+	}
+	return data
+}
+
+func TestClientOptions_Parse(t *testing.T) {
+	file := `
+# comment
+!includedir /etc/mysql/conf.d/
+[mysqld]
+user = mysql
+
+[client]
+host=db.example.com
+port = 3307
+user = "jdoe"
+password = 'se\'cret # not a comment'
+ssl_mode = VERIFY_IDENTITY # comment
+skip-column-names
+
+[remote]
+host = remote.example.com
+`
+	opts := clientOptions{}
+	if err := opts.parse(strings.NewReader(file), []string{"client"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]string{
+		"host":              "db.example.com",
+		"port":              "3307",
+		"user":              "jdoe",
+		"password":          "se'cret # not a comment",
+		"ssl-mode":          "VERIFY_IDENTITY",
+		"skip-column-names": "",
+	}
+	for name, value := range expected {
+		if opts[name] != value {
+			t.Errorf("expected %s to be %q, got %q", name, value, opts[name])
+		}
+	}
+	if len(opts) != len(expected) {
+		t.Errorf("expected only [client] options, got %v", opts)
+	}
+
+	if err := opts.parse(strings.NewReader(file), []string{"client", "remote"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts["host"] != "remote.example.com" {
+		t.Errorf("expected login path group to override [client], got %q", opts["host"])
+	}
+}
+
+func TestClientOptions_Endpoint(t *testing.T) {
+	testCases := map[string]struct {
+		opts     clientOptions
+		endpoint string
+	}{
+		"host":           {clientOptions{"host": "db.example.com"}, "db.example.com:3306"},
+		"host and port":  {clientOptions{"host": "db.example.com", "port": "3307"}, "db.example.com:3307"},
+		"ipv6":           {clientOptions{"host": "::1", "port": "3307"}, "[::1]:3307"},
+		"socket":         {clientOptions{"socket": "/var/run/mysqld/mysqld.sock"}, "/var/run/mysqld/mysqld.sock"},
+		"localhost":      {clientOptions{"host": "localhost", "socket": "/tmp/mysql.sock"}, "/tmp/mysql.sock"},
+		"remote ignores": {clientOptions{"host": "db.example.com", "socket": "/tmp/mysql.sock"}, "db.example.com:3306"},
+		"nothing":        {clientOptions{"user": "jdoe"}, ""},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if endpoint := tc.opts.endpoint(); endpoint != tc.endpoint {
+				t.Errorf("expected %q, got %q", tc.endpoint, endpoint)
+			}
+		})
+	}
+}
+
+func TestReadClientOptions_LoginPath(t *testing.T) {
+	dir := t.TempDir()
+	defaultsFile := filepath.Join(dir, "my.cnf")
+	err := os.WriteFile(defaultsFile, []byte("[client]\nuser = jdoe\nhost = db.example.com\n"), 0600)
+	if err != nil {
+		t.Fatalf("failed writing defaults file: %v", err)
+	}
+
+	loginFile := filepath.Join(dir, ".mylogin.cnf")
+	err = os.WriteFile(loginFile, encryptLoginPathFile(t, []string{
+		"[client]",
+		"password = \"client-secret\"",
+		"[admin]",
+		"user = \"admin\"",
+		"password = \"admin-secret\"",
+	}), 0600)
+	if err != nil {
+		t.Fatalf("failed writing login path file: %v", err)
+	}
+	t.Setenv("MYSQL_TEST_LOGIN_FILE", loginFile)
+
+	opts, err := readClientOptions(defaultsFile, "admin")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts["user"] != "admin" || opts["password"] != "admin-secret" || opts["host"] != "db.example.com" {
+		t.Errorf("expected login path to override the defaults file, got %v", opts)
+	}
+
+	opts, err = readClientOptions("", "client")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts["password"] != "client-secret" || opts["user"] != "" {
+		t.Errorf("expected only the client login path, got %v", opts)
+	}
+}
+
+func TestDecryptLoginPathFile_Invalid(t *testing.T) {
+	if _, err := decryptLoginPathFile([]byte("short")); err == nil {
+		t.Error("expected error for truncated file")
+	}
+
+	data := encryptLoginPathFile(t, []string{"[client]"})
+	if _, err := decryptLoginPathFile(data[:len(data)-1]); err == nil {
+		t.Error("expected error for truncated line")
+	}
+}
+
+func TestProviderConfigure_OptionFilePrecedence(t *testing.T) {
+	for _, env := range []string{"MYSQL_ENDPOINT", "MYSQL_USERNAME", "MYSQL_PASSWORD", "MYSQL_TLS_CONFIG"} {
+		t.Setenv(env, "")
+		os.Unsetenv(env)
+	}
+
+	defaultsFile := filepath.Join(t.TempDir(), "my.cnf")
+	err := os.WriteFile(defaultsFile, []byte("[client]\nhost = db.example.com\nuser = file-user\npassword = file-secret\nssl-mode = REQUIRED\n"), 0600)
+	if err != nil {
+		t.Fatalf("failed writing defaults file: %v", err)
+	}
+
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"defaults_file": defaultsFile,
+		"username":      "attribute-user",
+	})
+	meta, diags := providerConfigure(context.Background(), d)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	conf := meta.(*MySQLConfiguration).Config
+	if conf.Addr != "db.example.com:3306" {
+		t.Errorf("expected endpoint from defaults file, got %q", conf.Addr)
+	}
+	if conf.User != "attribute-user" {
+		t.Errorf("expected username attribute to take precedence, got %q", conf.User)
+	}
+	if conf.Passwd != "file-secret" {
+		t.Errorf("expected password from defaults file, got %q", conf.Passwd)
+	}
+	if conf.TLSConfig != "skip-verify" {
+		t.Errorf("expected ssl-mode REQUIRED to enable TLS without verification, got %q", conf.TLSConfig)
+	}
+
+	t.Setenv("MYSQL_PASSWORD", "env-secret")
+	d = schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"defaults_file": defaultsFile,
+	})
+	meta, diags = providerConfigure(context.Background(), d)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if conf := meta.(*MySQLConfiguration).Config; conf.Passwd != "env-secret" || conf.User != "file-user" {
+		t.Errorf("expected MYSQL_PASSWORD to take precedence, got user %q and password %q", conf.User, conf.Passwd)
+	}
+}
@@ -9,7 +9,6 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"log"
 	"net"
 	"net/http"
 	"net/url"
@@ -30,7 +29,6 @@ import (
 	"golang.org/x/oauth2"
 
 	"cloud.google.com/go/cloudsqlconn"
-	cloudsql "cloud.google.com/go/cloudsqlconn/mysql/mysql"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
@@ -56,20 +54,49 @@ const (
 )
 
 type DbConnection struct {
-	Db      *sql.DB
-	Version *version.Version
+	Db *sql.DB
+	// DiagnosticDb is a separate pool of one connection to look up sessions
+	// blocking the statements run on Db.
+	DiagnosticDb *sql.DB
+	*ServerInfo
+
+	// checked records the checks already run on the connection, like
+	// warnings that are only shown once per provider rather than for every
+	// resource.
+	checked sync.Map
+}
+
+// checkOnce tells whether check is run on the connection for the first time.
+func (c *DbConnection) checkOnce(check string) bool {
+	_, checked := c.checked.LoadOrStore(check, true)
+	return !checked
 }
 
 type MySQLConfiguration struct {
 	Config                 *mysql.Config
+	AuthToken              authTokenFunc
+	Dialer                 proxy.Dialer
+	CloudSQLDialer         *cloudsqlconn.Dialer
+	TransportKey           string
 	MaxConnLifetime        time.Duration
 	MaxOpenConns           int
 	ConnectRetryTimeoutSec time.Duration
+	// Endpoints are the addresses failed over between, in order of
+	// preference. Empty when only Config.Addr is used.
+	Endpoints []string
+	// RequirePrimary skips endpoints of servers that are read only.
+	RequirePrimary bool
+	// SQLMode overrides the sql_mode derived from the server version.
+	SQLMode *string
+	// InitStatements are run on every new connection.
+	InitStatements []string
+	StatementOptions
 }
 
 type RDSDataAPIConfiguration struct {
 	Config    *rds.Config
 	AWSConfig aws.Config
+	StatementOptions
 }
 
 type CustomTLS struct {
@@ -100,6 +127,36 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("MYSQL_ENDPOINT", nil),
 			},
 
+			"endpoints": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Ordered host:port addresses to connect to, failing over to the next one when a server can't be used. Overrides endpoint.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+
+			"require_primary": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Only connect to endpoints whose server isn't read only.",
+			},
+
+			"defaults_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("MYSQL_DEFAULTS_FILE", nil),
+				Description: "MySQL option file to read the [client] options from, e.g. ~/.my.cnf.",
+			},
+
+			"login_path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("MYSQL_LOGIN_PATH", nil),
+				Description: "Login path in the mysql_config_editor file ~/.mylogin.cnf to read options from.",
+			},
+
 			"username": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -135,6 +192,71 @@ func Provider() *schema.Provider {
 				}, nil),
 			},
 
+			"ssh_tunnel": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"host": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Address of the SSH bastion host in host or host:port format",
+						},
+						"user": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"private_key": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Sensitive:   true,
+							Description: "Private key used to authenticate, either PEM contents or a path to the key file",
+						},
+						"private_key_passphrase": {
+							Type:      schema.TypeString,
+							Optional:  true,
+							Sensitive: true,
+						},
+						"use_agent": {
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Authenticate using the SSH agent listening on SSH_AUTH_SOCK",
+						},
+						"known_hosts_file": {
+							Type:        schema.TypeString,
+							Optional:    true,
+							Default:     "~/.ssh/known_hosts",
+							Description: "known_hosts file used to verify the host keys of the bastion and jump hosts",
+						},
+						"insecure_ignore_host_key": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
+						"jump_host": {
+							Type:        schema.TypeList,
+							Optional:    true,
+							Description: "Hosts to hop through, in order, before connecting to the bastion host",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"host": {
+										Type:     schema.TypeString,
+										Required: true,
+									},
+									"user": {
+										Type:        schema.TypeString,
+										Optional:    true,
+										Description: "Defaults to the user of the ssh_tunnel block",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+
 			"tls": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -176,6 +298,23 @@ func Provider() *schema.Provider {
 				},
 			},
 
+			"sql_mode": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("MYSQL_SQL_MODE", nil),
+				Description: "The sql_mode of the provider's sessions. Defaults to NO_AUTO_CREATE_USER on MySQL 5.7 and to an empty mode otherwise.",
+			},
+
+			"init_statements": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Statements run on every new connection before it's used, e.g. SET SESSION lock_wait_timeout = 5.",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringIsNotWhiteSpace,
+				},
+			},
+
 			"max_conn_lifetime_sec": {
 				Type:     schema.TypeInt,
 				Optional: true,
@@ -206,6 +345,32 @@ func Provider() *schema.Provider {
 				Default:  300,
 			},
 
+			"audit_log_path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("MYSQL_AUDIT_LOG_PATH", nil),
+			},
+
+			"statement_retry_timeout_sec": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      60,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+
+			"statement_retry_backoff_ms": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      500,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+
+			"dry_run": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("MYSQL_DRY_RUN", false),
+			},
+
 			"iam_database_authentication": {
 				Type:     schema.TypeBool,
 				Optional: true,
@@ -317,23 +482,31 @@ func Provider() *schema.Provider {
 			},
 		},
 
-		DataSourcesMap: map[string]*schema.Resource{
+		DataSourcesMap: wrapResources(map[string]*schema.Resource{
 			"mysql_databases": dataSourceDatabases(),
 			"mysql_tables":    dataSourceTables(),
-		},
-
-		ResourcesMap: map[string]*schema.Resource{
-			"mysql_database":        resourceDatabase(),
-			"mysql_global_variable": resourceGlobalVariable(),
-			"mysql_grant":           resourceGrant(),
-			"mysql_role":            resourceRole(),
-			"mysql_sql":             resourceSql(),
-			"mysql_user_password":   resourceUserPassword(),
-			"mysql_user":            resourceUser(),
-			"mysql_ti_config":       resourceTiConfigVariable(),
-			"mysql_rds_config":      resourceRDSConfig(),
-			"mysql_default_roles":   resourceDefaultRoles(),
-		},
+		}),
+
+		ResourcesMap: wrapResources(map[string]*schema.Resource{
+			"mysql_database":           resourceDatabase(),
+			"mysql_event":              resourceEvent(),
+			"mysql_function":           resourceFunction(),
+			"mysql_global_variable":    resourceGlobalVariable(),
+			"mysql_grant":              resourceGrant(),
+			"mysql_procedure":          resourceProcedure(),
+			"mysql_role":               resourceRole(),
+			"mysql_sequence":           resourceSequence(),
+			"mysql_sql":                resourceSql(),
+			"mysql_table":              resourceTable(),
+			"mysql_table_partitioning": resourceTablePartitioning(),
+			"mysql_trigger":            resourceTrigger(),
+			"mysql_view":               resourceView(),
+			"mysql_user_password":      resourceUserPassword(),
+			"mysql_user":               resourceUser(),
+			"mysql_ti_config":          resourceTiConfigVariable(),
+			"mysql_rds_config":         resourceRDSConfig(),
+			"mysql_default_roles":      resourceDefaultRoles(),
+		}),
 
 		ConfigureContextFunc: providerConfigure,
 	}
@@ -411,8 +584,44 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 	var privateIp = d.Get("private_ip").(bool)
 	var tlsConfig = d.Get("tls").(string)
 	var tlsConfigStruct *tls.Config
+	var authToken authTokenFunc
+	var cloudSQLDialer *cloudsqlconn.Dialer
 	configKey := "default"
 
+	var endpoints []string
+	for _, e := range d.Get("endpoints").([]interface{}) {
+		endpoints = append(endpoints, e.(string))
+	}
+
+	// Option files only fill in what isn't set by attributes or environment
+	// variables.
+	var optionFileTLS *CustomTLS
+	defaultsFile, loginPath := d.Get("defaults_file").(string), d.Get("login_path").(string)
+	if defaultsFile != "" || loginPath != "" {
+		opts, err := readClientOptions(defaultsFile, loginPath)
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+		logConnection(ctx, "Using MySQL option files", map[string]interface{}{
+			"defaults_file": defaultsFile,
+			"login_path":    loginPath,
+		})
+
+		if endpoint == "" && len(endpoints) == 0 {
+			endpoint = opts.endpoint()
+		}
+		if username == "" {
+			username = opts["user"]
+		}
+		if password == "" {
+			password = opts["password"]
+		}
+		if !isProviderAttributeSet(d, "tls", "MYSQL_TLS_CONFIG") && opts.tlsConfig() != "" {
+			tlsConfig = opts.tlsConfig()
+		}
+		optionFileTLS = opts.customTLS()
+	}
+
 	// Read AWS config settings
 	var awsRdsIamAuth bool
 	var useRdsDataApi bool
@@ -427,20 +636,25 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 		secretArn = config["secret_arn"].(string)
 	}
 
+	customTLS := optionFileTLS
 	customTLSMap := d.Get("custom_tls").([]interface{})
 	if len(customTLSMap) > 0 {
-		log.Printf("[DEBUG] Using custom TLS config")
-		var customTLS CustomTLS
+		customTLS = &CustomTLS{}
 		customMap := customTLSMap[0].(map[string]interface{})
 		customTLSJson, err := json.Marshal(customMap)
 		if err != nil {
 			return nil, diag.Errorf("failed to marshal tls config %v with error %v", customTLSMap, err)
 		}
 
-		err = json.Unmarshal(customTLSJson, &customTLS)
+		err = json.Unmarshal(customTLSJson, customTLS)
 		if err != nil {
 			return nil, diag.Errorf("failed to unmarshal tls config %v with error %v", customTLSJson, err)
 		}
+	}
+
+	if customTLS != nil {
+		logConnection(ctx, "Using custom TLS config")
+		var err error
 
 		// Update the configKey if it is set
 		if customTLS.ConfigKey != "" {
@@ -451,7 +665,7 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 
 		var pem []byte
 		if customTLS.CACert != "" {
-			log.Printf("[DEBUG] Using custom CA cert")
+			logConnection(ctx, "Using custom CA cert")
 			rootCertPool := x509.NewCertPool()
 			if strings.HasPrefix(customTLS.CACert, "-----BEGIN") {
 				pem = []byte(customTLS.CACert)
@@ -468,7 +682,7 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 		}
 
 		if customTLS.ClientCert != "" && customTLS.ClientKey != "" {
-			log.Printf("[DEBUG] Using custom ClientCert & ClientKey")
+			logConnection(ctx, "Using custom ClientCert & ClientKey")
 			var cert tls.Certificate
 			if strings.HasPrefix(customTLS.ClientCert, "-----BEGIN") {
 				cert, err = tls.X509KeyPair([]byte(customTLS.ClientCert), []byte(customTLS.ClientKey))
@@ -481,20 +695,29 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 			tlsConfigStruct.Certificates = []tls.Certificate{cert}
 		}
 
-		// Register the config
-		err = mysql.RegisterTLSConfig(configKey, tlsConfigStruct)
-		if err != nil {
-			return nil, diag.Errorf("failed registering TLS config: %v", err)
-		}
+		// The config itself is passed to the connector, the key only keeps
+		// differently configured providers apart in the connection cache.
 		tlsConfig = configKey
 	}
 
+	if len(endpoints) > 0 {
+		if awsRdsIamAuth {
+			return nil, diag.Errorf("endpoints can't be used with AWS RDS IAM authentication")
+		}
+		for _, e := range endpoints {
+			if e == "" || e[0] == '/' || strings.Contains(e, "://") {
+				return nil, diag.Errorf("endpoints must be host:port addresses, got %q", e)
+			}
+		}
+		endpoint = endpoints[0]
+	}
+
 	proto := "tcp"
 	if len(endpoint) > 0 && endpoint[0] == '/' {
 		proto = "unix"
 	} else if awsRdsIamAuth || strings.HasPrefix(endpoint, "aws://") {
 		// AWS RDS IAM authentication (both new and legacy)
-		log.Printf("[DEBUG] Using AWS RDS IAM authentication")
+		logConnection(ctx, "Using AWS RDS IAM authentication")
 
 		endpoint = strings.TrimPrefix(endpoint, "aws://")
 
@@ -512,36 +735,41 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 			return nil, diag.Errorf("failed to build AWS config: %v", err)
 		}
 
-		// Generate AWS RDS IAM auth token
-		password, err = awsRdsAuth.BuildAuthToken(ctx, endpoint, awsConfigObj.Region, username, awsConfigObj.Credentials)
-		if err != nil {
+		// AWS RDS IAM auth tokens expire after 15 minutes, so a new one is
+		// generated for every new connection instead of using a fixed password.
+		rdsEndpoint, rdsUser := endpoint, username
+		authToken = func(ctx context.Context) (string, error) {
+			return awsRdsAuth.BuildAuthToken(ctx, rdsEndpoint, awsConfigObj.Region, rdsUser, awsConfigObj.Credentials)
+		}
+
+		// Fail early if the credentials can't produce a token at all.
+		if _, err = authToken(ctx); err != nil {
 			return nil, diag.Errorf("failed to build AWS RDS auth token: %v", err)
 		}
+		password = ""
 
 	} else if strings.HasPrefix(endpoint, "cloudsql://") {
 		proto = "cloudsql"
 		endpoint = strings.ReplaceAll(endpoint, "cloudsql://", "")
-		var err error
+		var opts []cloudsqlconn.Option
 		if iamAuth { // Access token will be in the password field
-
-			var opts []cloudsqlconn.Option
-
 			token := oauth2.StaticTokenSource(&oauth2.Token{
 				AccessToken: password,
 			})
 			opts = append(opts, cloudsqlconn.WithIAMAuthN())
 			opts = append(opts, cloudsqlconn.WithIAMAuthNTokenSources(token, token))
-			_, err = cloudsql.RegisterDriver("cloudsql", opts...)
 		} else {
 			var endpointParams []cloudsqlconn.DialOption
 			if privateIp {
 				endpointParams = append(endpointParams, cloudsqlconn.WithPrivateIP())
 			}
-
-			_, err = cloudsql.RegisterDriver("cloudsql", cloudsqlconn.WithDefaultDialOptions(endpointParams...))
+			opts = append(opts, cloudsqlconn.WithDefaultDialOptions(endpointParams...))
 		}
+
+		var err error
+		cloudSQLDialer, err = cloudsqlconn.NewDialer(context.Background(), opts...)
 		if err != nil {
-			return nil, diag.Errorf("failed to register driver %v", err)
+			return nil, diag.Errorf("failed to create Cloud SQL dialer %v", err)
 		}
 
 	} else if strings.HasPrefix(endpoint, "azure://") {
@@ -572,10 +800,13 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 		}
 
 		if azTenantId != "" && azClientId != "" && azClientSecret != "" {
-			log.Printf("[DEBUG] Using Azure Client Secret Credentials: client_id = %s, tenant_id = %s", azClientId, azTenantId)
+			logConnection(ctx, "Using Azure Client Secret Credentials", map[string]interface{}{
+				"client_id": azClientId,
+				"tenant_id": azTenantId,
+			})
 			azCredential, err = azidentity.NewClientSecretCredential(azTenantId, azClientId, azClientSecret, nil)
 		} else {
-			log.Printf("[DEBUG] Using Azure Default Credentials")
+			logConnection(ctx, "Using Azure Default Credentials")
 			azCredential, err = azidentity.NewDefaultAzureCredential(nil)
 		}
 		// Azure AD does not support native password authentication but go-sql-driver/mysql
@@ -583,34 +814,26 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 		allowClearTextPasswords = true
 		endpoint = strings.ReplaceAll(endpoint, "azure://", "")
 
-		var azScope string
-		switch azEnvironment {
-		case azEnvChina:
-			azScope = "https://ossrdbms-aad.database.chinacloudapi.cn"
-		case azEnvGerman:
-			azScope = "https://ossrdbms-aad.database.chinacloudapi.de"
-		case azEnvUSGovernment:
-			azScope = "https://ossrdbms-aad.database.usgovcloudapi.net"
-		case azEnvPublic:
-			fallthrough
-		default:
-			azScope = "https://ossrdbms-aad.database.windows.net"
-		}
-
 		if err != nil {
 			return nil, diag.Errorf("failed to create Azure credential %v", err)
 		}
 
-		azToken, err := azCredential.GetToken(
-			ctx,
-			policy.TokenRequestOptions{Scopes: []string{azScope + "/.default"}},
-		)
+		// Azure AD access tokens expire, so a new one is requested for every new
+		// connection. The credential caches tokens until they are close to expiry.
+		tokenOptions := policy.TokenRequestOptions{Scopes: []string{azureTokenScope(azEnvironment)}}
+		authToken = func(ctx context.Context) (string, error) {
+			azToken, err := azCredential.GetToken(ctx, tokenOptions)
+			if err != nil {
+				return "", err
+			}
+			return azToken.Token, nil
+		}
 
-		if err != nil {
+		// Fail early if the credential can't obtain a token at all.
+		if _, err = authToken(ctx); err != nil {
 			return nil, diag.Errorf("failed to get token from Azure AD: %v", err)
 		}
-
-		password = azToken.Token
+		password = ""
 	}
 
 	// Parse connection parameters
@@ -618,6 +841,19 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 		return nil, diag.FromErr(err)
 	}
 
+	statementOptions := StatementOptions{
+		DryRun:       d.Get("dry_run").(bool),
+		RetryTimeout: time.Duration(d.Get("statement_retry_timeout_sec").(int)) * time.Second,
+		RetryBackoff: time.Duration(d.Get("statement_retry_backoff_ms").(int)) * time.Millisecond,
+	}
+	if auditLogPath := d.Get("audit_log_path").(string); auditLogPath != "" {
+		var err error
+		statementOptions.AuditLog, err = newAuditLog(auditLogPath)
+		if err != nil {
+			return nil, diag.FromErr(err)
+		}
+	}
+
 	if useRdsDataApi {
 		awsConfigObj, err := buildAwsConfig(ctx, awsConfigBlock)
 		if err != nil {
@@ -638,8 +874,9 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 		}
 
 		return &RDSDataAPIConfiguration{
-			Config:    rdsConfig,
-			AWSConfig: awsConfigObj,
+			Config:           rdsConfig,
+			AWSConfig:        awsConfigObj,
+			StatementOptions: statementOptions,
 		}, nil
 	}
 
@@ -659,25 +896,58 @@ func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}
 		conf.TLS = tlsConfigStruct
 	}
 
-	dialer, err := makeDialer(d)
+	dialer, err := makeDialer(ctx, d)
 	if err != nil {
 		return nil, diag.Errorf("failed making dialer: %v", err)
 	}
 
-	mysql.RegisterDialContext("tcp", func(ctx context.Context, network string) (net.Conn, error) {
-		return dialer.Dial("tcp", network)
-	})
+	var initStatements []string
+	for _, stmt := range d.Get("init_statements").([]interface{}) {
+		initStatements = append(initStatements, stmt.(string))
+	}
 
 	mysqlConf := &MySQLConfiguration{
 		Config:                 &conf,
+		AuthToken:              authToken,
+		Dialer:                 dialer,
+		CloudSQLDialer:         cloudSQLDialer,
+		TransportKey:           transportKey(d),
 		MaxConnLifetime:        time.Duration(d.Get("max_conn_lifetime_sec").(int)) * time.Second,
 		MaxOpenConns:           d.Get("max_open_conns").(int),
 		ConnectRetryTimeoutSec: time.Duration(d.Get("connect_retry_timeout_sec").(int)) * time.Second,
+		Endpoints:              endpoints,
+		RequirePrimary:         d.Get("require_primary").(bool),
+		InitStatements:         initStatements,
+		StatementOptions:       statementOptions,
+	}
+
+	if isProviderAttributeSet(d, "sql_mode", "MYSQL_SQL_MODE") {
+		sqlMode := d.Get("sql_mode").(string)
+		mysqlConf.SQLMode = &sqlMode
 	}
 
 	return mysqlConf, nil
 }
 
+// azureTokenScope returns the Azure AD scope of MySQL tokens in the given
+// Azure environment.
+func azureTokenScope(azEnvironment string) string {
+	var azScope string
+	switch azEnvironment {
+	case azEnvChina:
+		azScope = "https://ossrdbms-aad.database.chinacloudapi.cn"
+	case azEnvGerman:
+		azScope = "https://ossrdbms-aad.database.chinacloudapi.de"
+	case azEnvUSGovernment:
+		azScope = "https://ossrdbms-aad.database.usgovcloudapi.net"
+	case azEnvPublic:
+		fallthrough
+	default:
+		azScope = "https://ossrdbms-aad.database.windows.net"
+	}
+	return azScope + "/.default"
+}
+
 var identQuoteReplacer = strings.NewReplacer("`", "``")
 
 // httpProxyDialer implements the proxy.Dialer interface for HTTP proxies
@@ -807,7 +1077,69 @@ func shouldUseProxy(endpoint, noProxy string) bool {
 	return true
 }
 
-func makeDialer(d *schema.ResourceData) (proxy.Dialer, error) {
+func parseSSHTunnelConfig(d *schema.ResourceData) *SSHTunnelConfig {
+	sshTunnelList := d.Get("ssh_tunnel").([]interface{})
+	if len(sshTunnelList) == 0 || sshTunnelList[0] == nil {
+		return nil
+	}
+
+	sshTunnel := sshTunnelList[0].(map[string]interface{})
+	conf := &SSHTunnelConfig{
+		Host:                  sshTunnel["host"].(string),
+		User:                  sshTunnel["user"].(string),
+		PrivateKey:            sshTunnel["private_key"].(string),
+		PrivateKeyPassphrase:  sshTunnel["private_key_passphrase"].(string),
+		UseAgent:              sshTunnel["use_agent"].(bool),
+		KnownHostsFile:        sshTunnel["known_hosts_file"].(string),
+		InsecureIgnoreHostKey: sshTunnel["insecure_ignore_host_key"].(bool),
+	}
+	for _, jumpHostIf := range sshTunnel["jump_host"].([]interface{}) {
+		jumpHost := jumpHostIf.(map[string]interface{})
+		conf.JumpHosts = append(conf.JumpHosts, SSHJumpHost{
+			Host: jumpHost["host"].(string),
+			User: jumpHost["user"].(string),
+		})
+	}
+	return conf
+}
+
+// isProviderAttributeSet tells whether attr is set in the configuration or
+// by its environment variable, as opposed to left at its default.
+func isProviderAttributeSet(d *schema.ResourceData, attr, envVar string) bool {
+	if _, ok := os.LookupEnv(envVar); ok {
+		return true
+	}
+	rawConfig := d.GetRawConfig()
+	if rawConfig.IsNull() || !rawConfig.IsKnown() {
+		return false
+	}
+	return !rawConfig.GetAttr(attr).IsNull()
+}
+
+// transportKey summarizes the provider settings that affect how connections
+// are made, but aren't part of the DSN.
+func transportKey(d *schema.ResourceData) string {
+	return hashSum(fmt.Sprint(
+		d.Get("proxy"),
+		d.Get("no_proxy"),
+		d.Get("ssh_tunnel"),
+		d.Get("custom_tls"),
+		d.Get("private_ip"),
+		d.Get("endpoints"),
+		d.Get("require_primary"),
+		d.Get("defaults_file"),
+		d.Get("login_path"),
+		d.Get("sql_mode"),
+		d.Get("init_statements"),
+	))
+}
+
+func makeDialer(ctx context.Context, d *schema.ResourceData) (proxy.Dialer, error) {
+	if sshTunnel := parseSSHTunnelConfig(d); sshTunnel != nil {
+		logConnection(ctx, "Using SSH tunnel", map[string]interface{}{"ssh_host": sshTunnel.Host})
+		return newSSHTunnelDialer(sshTunnel)
+	}
+
 	proxyArg := ""
 	if v := d.Get("proxy"); v != nil {
 		proxyArg = v.(string)
@@ -822,6 +1154,9 @@ func makeDialer(d *schema.ResourceData) (proxy.Dialer, error) {
 	if v := d.Get("endpoint"); v != nil {
 		endpoint = v.(string)
 	}
+	if endpoints := d.Get("endpoints").([]interface{}); len(endpoints) > 0 {
+		endpoint, _ = endpoints[0].(string)
+	}
 
 	// Use explicit proxy if configured and not excluded by no_proxy
 	if len(proxyArg) > 0 && shouldUseProxy(endpoint, noProxyArg) {
@@ -832,7 +1167,7 @@ func makeDialer(d *schema.ResourceData) (proxy.Dialer, error) {
 
 		// Handle HTTP and HTTPS proxies differently from SOCKS
 		if proxyURL.Scheme == "http" || proxyURL.Scheme == "https" {
-			log.Printf("[DEBUG] Using HTTP/HTTPS proxy: %s", proxyArg)
+			logConnection(ctx, "Using HTTP/HTTPS proxy", map[string]interface{}{"proxy": proxyURL.Redacted()})
 
 			// Create an HTTP transport with the proxy
 			httpTransport := &http.Transport{
@@ -863,69 +1198,6 @@ func quoteIdentifier(in string) string {
 	return fmt.Sprintf("`%s`", identQuoteReplacer.Replace(in))
 }
 
-func serverVersion(db *sql.DB) (*version.Version, error) {
-	var versionString string
-	err := db.QueryRow("SELECT @@GLOBAL.version").Scan(&versionString)
-	if err != nil {
-		return nil, err
-	}
-
-	versionString = strings.SplitN(versionString, ":", 2)[0]
-	return version.NewVersion(versionString)
-}
-
-func serverVersionString(db *sql.DB) (string, error) {
-	var versionString string
-	err := db.QueryRow("SELECT @@GLOBAL.version").Scan(&versionString)
-	if err != nil {
-		return "", err
-	}
-
-	return versionString, nil
-}
-
-// serverTiDB returns:
-// - it is a TiDB instance
-// - tidbVersion
-// - mysqlCompatibilityVersion
-// - err
-func serverTiDB(db *sql.DB) (bool, string, string, error) {
-	currentVersionString, err := serverVersionString(db)
-	if err != nil {
-		return false, "", "", err
-	}
-
-	if strings.Contains(currentVersionString, "TiDB") {
-		versions := strings.SplitN(currentVersionString, "-", 3)
-		return true, versions[2], versions[0], nil
-	}
-
-	return false, "", "", nil
-}
-
-func serverRds(db *sql.DB) (bool, error) {
-	var metadataVersionString string
-	err := db.QueryRow("SELECT @@GLOBAL.datadir").Scan(&metadataVersionString)
-	if err != nil {
-		return false, err
-	}
-
-	if strings.Contains(metadataVersionString, "rds") {
-		return true, nil
-	}
-
-	return false, nil
-}
-
-func serverMariaDB(db *sql.DB) (bool, error) {
-	versionString, err := serverVersionString(db)
-	if err != nil {
-		return false, err
-	}
-
-	return strings.Contains(versionString, "MariaDB"), nil
-}
-
 func connectToMySQL(ctx context.Context, conf *MySQLConfiguration) (*sql.DB, error) {
 	conn, err := connectToMySQLInternal(ctx, conf)
 	if err != nil {
@@ -940,10 +1212,10 @@ func connectToMySQLInternal(ctx context.Context, conf *MySQLConfiguration) (*DbC
 	connectionCacheMtx.Lock()
 	defer connectionCacheMtx.Unlock()
 
-	dsn := conf.Config.FormatDSN()
-	log.Printf("[DEBUG] Using dsn: %s", dsn)
-	if connectionCache[dsn] != nil {
-		return connectionCache[dsn], nil
+	logConnection(ctx, "Using dsn", map[string]interface{}{"dsn": redactDSN(conf.Config)})
+	cacheKey := conf.connectionCacheKey()
+	if connectionCache[cacheKey] != nil {
+		return connectionCache[cacheKey], nil
 	}
 
 	connection, err := createNewConnection(ctx, conf)
@@ -951,26 +1223,36 @@ func connectToMySQLInternal(ctx context.Context, conf *MySQLConfiguration) (*DbC
 		return nil, fmt.Errorf("could not create new connection: %v", err)
 	}
 
-	connectionCache[dsn] = connection
-	return connectionCache[dsn], nil
+	connectionCache[cacheKey] = connection
+	return connectionCache[cacheKey], nil
+}
+
+// connectionCacheKey identifies the connection pool of conf. The DSN alone
+// doesn't cover how the server is reached, so aliased providers differing
+// only in e.g. their proxy must not share a pool.
+func (conf *MySQLConfiguration) connectionCacheKey() string {
+	if conf.TransportKey == "" {
+		return conf.Config.FormatDSN()
+	}
+	return conf.Config.FormatDSN() + "#" + conf.TransportKey
+}
+
+// connectionCacheKey identifies the Data API connection of conf, keeping it
+// apart from the DSNs of MySQL connections.
+func (conf *RDSDataAPIConfiguration) connectionCacheKey() string {
+	return "rds-data-api:" + conf.Config.ToDSN()
 }
 
 func createNewConnection(ctx context.Context, conf *MySQLConfiguration) (*DbConnection, error) {
 	var db *sql.DB
 	var err error
 
-	driverName := "mysql"
-	if conf.Config.Net == "cloudsql" {
-		driverName = "cloudsql"
-	}
-	log.Printf("[DEBUG] Using driverName: %s", driverName)
-
 	// When provisioning a database server there can often be a lag between
 	// when Terraform thinks it's available and when it is actually available.
 	// This is particularly acute when provisioning a server and then immediately
 	// trying to provision a database on it.
 	retryError := retry.RetryContext(ctx, conf.ConnectRetryTimeoutSec, func() *retry.RetryError {
-		db, err = sql.Open(driverName, conf.Config.FormatDSN())
+		db, err = openDB(conf)
 		if err != nil {
 			if mysqlErrorNumber(err) != 0 || cloudsqlErrorNumber(err) != 0 || ctx.Err() != nil {
 				return retry.NonRetryableError(err)
@@ -994,10 +1276,15 @@ func createNewConnection(ctx context.Context, conf *MySQLConfiguration) (*DbConn
 		return nil, fmt.Errorf("could not connect to server: %s", retryError)
 	}
 
-	currentVersion, err := serverVersion(db)
+	serverInfo, err := detectServerInfo(db, conf.Config.Addr)
 	if err != nil {
-		return nil, fmt.Errorf("failed getting server version: %v", err)
+		return nil, err
 	}
+	logConnection(ctx, "Detected server", map[string]interface{}{
+		"flavor":   string(serverInfo.Flavor),
+		"platform": string(serverInfo.Platform),
+		"version":  serverInfo.VersionString,
+	})
 
 	// Close the temporary connection - we'll create a proper pooled one
 	db.Close()
@@ -1006,18 +1293,30 @@ func createNewConnection(ctx context.Context, conf *MySQLConfiguration) (*DbConn
 		conf.Config.Params = make(map[string]string)
 	}
 
-	setSQLModeParam(conf.Config.Params, currentVersion)
+	if conf.SQLMode != nil {
+		conf.Config.Params["sql_mode"] = quoteString(*conf.SQLMode)
+	} else {
+		setSQLModeParam(conf.Config.Params, serverInfo.Version)
+	}
 
-	db, err = sql.Open(driverName, conf.Config.FormatDSN())
+	db, err = openDB(conf)
 	if err != nil {
 		return nil, fmt.Errorf("could not open database: %v", err)
 	}
 
 	configureConnectionPool(db, conf.MaxConnLifetime, conf.MaxOpenConns)
 
+	// Nothing is dialed until a statement times out.
+	diagnosticDb, err := openDB(conf)
+	if err != nil {
+		return nil, fmt.Errorf("could not open database: %v", err)
+	}
+	configureConnectionPool(diagnosticDb, conf.MaxConnLifetime, 1)
+
 	return &DbConnection{
-		Db:      db,
-		Version: currentVersion,
+		Db:           db,
+		DiagnosticDb: diagnosticDb,
+		ServerInfo:   serverInfo,
 	}, nil
 }
 
@@ -174,6 +174,22 @@ func TestBuildAwsConfig(t *testing.T) {
 	}
 }
 
+func TestAzureTokenScope(t *testing.T) {
+	testCases := map[string]string{
+		"":                "https://ossrdbms-aad.database.windows.net/.default",
+		azEnvPublic:       "https://ossrdbms-aad.database.windows.net/.default",
+		azEnvChina:        "https://ossrdbms-aad.database.chinacloudapi.cn/.default",
+		azEnvGerman:       "https://ossrdbms-aad.database.chinacloudapi.de/.default",
+		azEnvUSGovernment: "https://ossrdbms-aad.database.usgovcloudapi.net/.default",
+	}
+
+	for env, expected := range testCases {
+		if scope := azureTokenScope(env); scope != expected {
+			t.Errorf("environment %q: expected scope %s, got %s", env, expected, scope)
+		}
+	}
+}
+
 func testAccPreCheck(t *testing.T) {
 	ctx := context.Background()
 	for _, name := range []string{"MYSQL_ENDPOINT", "MYSQL_USERNAME"} {
@@ -191,21 +207,21 @@ func testAccPreCheck(t *testing.T) {
 	}
 }
 
-func testAccPreCheckSkipNotRds(t *testing.T) {
-	testAccPreCheck(t)
-
-	ctx := context.Background()
-	db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+// testAccServerInfo returns the flavor, version and platform of the server
+// the acceptance tests run against.
+func testAccServerInfo(t *testing.T, check string) *ServerInfo {
+	serverInfo, err := getServerInfoFromMeta(context.Background(), testAccProvider.Meta())
 	if err != nil {
-		return
+		t.Fatalf("Cannot get server info (%s): %v", check, err)
 	}
+	return serverInfo
+}
 
-	rdsEnabled, err := serverRds(db)
-	if err != nil {
-		return
-	}
+func testAccPreCheckSkipNotRds(t *testing.T) {
+	testAccPreCheck(t)
 
-	if !rdsEnabled {
+	serverInfo := testAccServerInfo(t, "SkipNotRds")
+	if serverInfo.Platform != PlatformRDS && serverInfo.Platform != PlatformAurora {
 		t.Skip("Skip on non RDS instance")
 	}
 }
@@ -213,8 +229,7 @@ func testAccPreCheckSkipNotRds(t *testing.T) {
 func testAccPreCheckSkipRds(t *testing.T) {
 	testAccPreCheck(t)
 
-	ctx := context.Background()
-	db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+	serverInfo, err := getServerInfoFromMeta(context.Background(), testAccProvider.Meta())
 	if err != nil {
 		if strings.Contains(err.Error(), "SUPER privilege(s) for this operation") {
 			t.Skip("Skip on RDS")
@@ -222,12 +237,7 @@ func testAccPreCheckSkipRds(t *testing.T) {
 		return
 	}
 
-	rdsEnabled, err := serverRds(db)
-	if err != nil {
-		return
-	}
-
-	if rdsEnabled {
+	if serverInfo.Platform == PlatformRDS || serverInfo.Platform == PlatformAurora {
 		t.Skip("Skip on RDS")
 	}
 }
@@ -235,20 +245,7 @@ func testAccPreCheckSkipRds(t *testing.T) {
 func testAccPreCheckSkipTiDB(t *testing.T) {
 	testAccPreCheck(t)
 
-	ctx := context.Background()
-	db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
-	if err != nil {
-		t.Fatalf("Cannot connect to DB (SkipTiDB): %v", err)
-		return
-	}
-
-	currentVersionString, err := serverVersionString(db)
-	if err != nil {
-		t.Fatalf("Cannot get DB version string (SkipTiDB): %v", err)
-		return
-	}
-
-	if strings.Contains(currentVersionString, "TiDB") {
+	if testAccServerInfo(t, "SkipTiDB").Flavor == FlavorTiDB {
 		t.Skip("Skip on TiDB")
 	}
 }
@@ -256,20 +253,7 @@ func testAccPreCheckSkipTiDB(t *testing.T) {
 func testAccPreCheckSkipMariaDB(t *testing.T) {
 	testAccPreCheck(t)
 
-	ctx := context.Background()
-	db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
-	if err != nil {
-		t.Fatalf("Cannot connect to DB (SkipMariaDB): %v", err)
-		return
-	}
-
-	currentVersionString, err := serverVersionString(db)
-	if err != nil {
-		t.Fatalf("Cannot get DB version string (SkipMariaDB): %v", err)
-		return
-	}
-
-	if strings.Contains(currentVersionString, "MariaDB") {
+	if testAccServerInfo(t, "SkipMariaDB").Flavor == FlavorMariaDB {
 		t.Skip("Skip on MariaDB")
 	}
 }
@@ -277,20 +261,7 @@ func testAccPreCheckSkipMariaDB(t *testing.T) {
 func testAccPreCheckRequireMariaDB(t *testing.T) {
 	testAccPreCheck(t)
 
-	ctx := context.Background()
-	db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
-	if err != nil {
-		t.Fatalf("Cannot connect to DB (RequireMariaDB): %v", err)
-		return
-	}
-
-	currentVersionString, err := serverVersionString(db)
-	if err != nil {
-		t.Fatalf("Cannot get DB version string (RequireMariaDB): %v", err)
-		return
-	}
-
-	if !strings.Contains(currentVersionString, "MariaDB") {
+	if testAccServerInfo(t, "RequireMariaDB").Flavor != FlavorMariaDB {
 		t.Skip("Test requires MariaDB")
 	}
 }
@@ -302,60 +273,50 @@ func testAccPreCheckSkipNotMySQL8(t *testing.T) {
 func testAccPreCheckSkipNotMySQLVersionMin(t *testing.T, minVersion string) {
 	testAccPreCheck(t)
 
-	ctx := context.Background()
-	db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
-	if err != nil {
-		t.Fatalf("Cannot connect to DB (SkipNotMySQL8): %v", err)
-		return
-	}
-
-	currentVersion, err := serverVersion(db)
-	if err != nil {
-		t.Fatalf("Cannot get DB version string (SkipNotMySQL8): %v", err)
-		return
-	}
-
+	// The version of TiDB is the version of MySQL it's compatible with,
+	// followed by its own, which go-version takes as a pre-release.
 	versionMin, _ := version.NewVersion(minVersion)
-	if currentVersion.LessThan(versionMin) {
-		// TiDB 7.x series advertises as 8.0 mysql so we batch its testing strategy with Mysql8
-		isTiDB, tidbVersion, mysqlCompatibilityVersion, err := serverTiDB(db)
-		if err != nil {
-			t.Fatalf("Cannot get DB version string (SkipNotMySQL8): %v", err)
-			return
-		}
-		if isTiDB {
-			mysqlVersion, err := version.NewVersion(mysqlCompatibilityVersion)
-			if err != nil {
-				t.Fatalf("Cannot get DB version string for TiDB (SkipNotMySQL8): %s %s %v", tidbVersion, mysqlCompatibilityVersion, err)
-				return
-			}
-			if mysqlVersion.LessThan(versionMin) {
-				t.Skip("Skip on MySQL8")
-			}
-		}
-
-		t.Skip("Skip on MySQL8")
+	if testAccServerInfo(t, "SkipNotMySQLVersionMin").Version.Core().LessThan(versionMin) {
+		t.Skip(fmt.Sprintf("Skip on MySQL older than %s", minVersion))
 	}
 }
 
 func testAccPreCheckSkipNotTiDB(t *testing.T) {
 	testAccPreCheck(t)
 
-	ctx := context.Background()
-	db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
-	if err != nil {
-		t.Fatalf("Cannot connect to DB (SkipNotTiDB): %v", err)
-		return
+	serverInfo := testAccServerInfo(t, "SkipNotTiDB")
+	if serverInfo.Flavor != FlavorTiDB {
+		t.Skip(fmt.Sprintf("Skip on MySQL %s", serverInfo.VersionString))
 	}
+}
 
-	currentVersionString, err := serverVersionString(db)
-	if err != nil {
-		t.Fatalf("Cannot get DB version string (SkipNotTiDB): %v", err)
-		return
+func TestProviderConfigure_SQLMode(t *testing.T) {
+	t.Setenv("MYSQL_ENDPOINT", "localhost:3306")
+	t.Setenv("MYSQL_SQL_MODE", "")
+	os.Unsetenv("MYSQL_SQL_MODE")
+
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{})
+	meta, diags := providerConfigure(context.Background(), d)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if meta.(*MySQLConfiguration).SQLMode != nil {
+		t.Error("expected sql_mode to be derived from the server version by default")
 	}
 
-	if !strings.Contains(currentVersionString, "TiDB") {
-		msg := fmt.Sprintf("Skip on MySQL %s", currentVersionString)
-		t.Skip(msg)
+	t.Setenv("MYSQL_SQL_MODE", "STRICT_TRANS_TABLES,NO_ZERO_DATE")
+	d = schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"init_statements": []interface{}{"SET SESSION sql_log_bin = 0"},
+	})
+	meta, diags = providerConfigure(context.Background(), d)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	conf := meta.(*MySQLConfiguration)
+	if conf.SQLMode == nil || *conf.SQLMode != "STRICT_TRANS_TABLES,NO_ZERO_DATE" {
+		t.Errorf("expected sql_mode from MYSQL_SQL_MODE, got %v", conf.SQLMode)
+	}
+	if len(conf.InitStatements) != 1 || conf.InitStatements[0] != "SET SESSION sql_log_bin = 0" {
+		t.Errorf("expected init statements to be passed on, got %v", conf.InitStatements)
 	}
 }
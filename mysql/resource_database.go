@@ -5,7 +5,6 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"log"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -55,7 +54,7 @@ func CreateDatabase(ctx context.Context, d *schema.ResourceData, meta interface{
 	}
 
 	stmtSQL := databaseConfigSQL("CREATE", d)
-	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	logSQL(ctx, stmtSQL)
 
 	_, err = db.ExecContext(ctx, stmtSQL)
 	if err != nil {
@@ -74,7 +73,7 @@ func UpdateDatabase(ctx context.Context, d *schema.ResourceData, meta interface{
 	}
 
 	stmtSQL := databaseConfigSQL("ALTER", d)
-	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	logSQL(ctx, stmtSQL)
 
 	_, err = db.ExecContext(ctx, stmtSQL)
 	if err != nil {
@@ -98,7 +97,7 @@ func ReadDatabase(ctx context.Context, d *schema.ResourceData, meta interface{})
 	name := d.Id()
 	stmtSQL := "SHOW CREATE DATABASE " + quoteIdentifier(name)
 
-	log.Println("[DEBUG] Executing query:", stmtSQL)
+	logSQL(ctx, stmtSQL)
 	var createSQL, _database string
 	err = db.QueryRowContext(ctx, stmtSQL).Scan(&_database, &createSQL)
 	if err != nil {
@@ -131,7 +130,7 @@ func ReadDatabase(ctx context.Context, d *schema.ResourceData, meta interface{})
 		*/
 		var empty interface{}
 
-		res := db.QueryRow(stmtSQL, defaultCharset).Scan(&defaultCollation, &empty)
+		res := db.QueryRowContext(ctx, stmtSQL, defaultCharset).Scan(&defaultCollation, &empty)
 
 		if res != nil {
 			if errors.Is(res, sql.ErrNoRows) {
@@ -157,7 +156,7 @@ func DeleteDatabase(ctx context.Context, d *schema.ResourceData, meta interface{
 
 	name := d.Id()
 	stmtSQL := "DROP DATABASE " + quoteIdentifier(name)
-	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	logSQL(ctx, stmtSQL)
 
 	_, err = db.ExecContext(ctx, stmtSQL)
 	if err != nil {
@@ -2,13 +2,10 @@ package mysql
 
 import (
 	"context"
-	"database/sql"
 	"errors"
 	"fmt"
-	"log"
 	"strings"
 
-	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -50,19 +47,19 @@ func resourceDefaultRoles() *schema.Resource {
 }
 
 func checkDefaultRolesSupport(ctx context.Context, meta interface{}) error {
-	ver, _ := version.NewVersion("8.0.0")
-	if getVersionFromMeta(ctx, meta).LessThan(ver) {
+	serverInfo, err := getServerInfoFromMeta(ctx, meta)
+	if err != nil {
+		return err
+	}
+
+	if !serverInfo.Capabilities.DefaultRoles {
 		return errors.New("MySQL version must be at least 8.0.0")
 	}
 	return nil
 }
 
-func alterUserDefaultRoles(ctx context.Context, db *sql.DB, user, host string, roles []string) error {
-	isMariaDB, err := serverMariaDB(db)
-	if err != nil {
-		return err
-	}
-
+func alterUserDefaultRoles(ctx context.Context, db *StatementExecutor, user, host string, roles []string) error {
+	isMariaDB := db.Server.Flavor == FlavorMariaDB
 	if isMariaDB && len(roles) > 1 {
 		return fmt.Errorf("MariaDB supports at most one default role per user, got %d", len(roles))
 	}
@@ -81,8 +78,8 @@ func alterUserDefaultRoles(ctx context.Context, db *sql.DB, user, host string, r
 		stmtSQL = fmt.Sprintf("ALTER USER '%s'@'%s' DEFAULT ROLE %s", user, host, rolesFragment)
 	}
 
-	log.Println("[DEBUG] Executing statement:", stmtSQL)
-	_, err = db.ExecContext(ctx, stmtSQL)
+	logSQL(ctx, stmtSQL)
+	_, err := db.ExecContext(ctx, stmtSQL)
 	if err != nil {
 		return fmt.Errorf("failed executing SQL: %w", err)
 	}
@@ -153,17 +150,12 @@ func ReadDefaultRoles(ctx context.Context, d *schema.ResourceData, meta interfac
 		return diag.Errorf("cannot use default roles: %v", err)
 	}
 
-	isMariaDB, err := serverMariaDB(db)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-
 	stmtSQL := "SELECT default_role_user FROM mysql.default_roles WHERE user = ? AND host = ?"
-	if isMariaDB {
+	if db.Server.Flavor == FlavorMariaDB {
 		stmtSQL = "SELECT default_role FROM mysql.user WHERE user = ? AND host = ?"
 	}
 
-	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	logSQL(ctx, stmtSQL)
 
 	rows, err := db.QueryContext(ctx, stmtSQL, d.Get("user").(string), d.Get("host").(string))
 	if err != nil {
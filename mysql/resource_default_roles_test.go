@@ -71,7 +71,7 @@ func TestAccDefaultRoles_mariadbRejectsMultiple(t *testing.T) {
 	testAccPreCheckRequireMariaDB(t)
 
 	ctx := context.Background()
-	db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+	db, err := getDatabaseFromMeta(ctx, testAccProvider.Meta())
 	if err != nil {
 		t.Fatalf("cannot connect to DB: %v", err)
 	}
@@ -87,13 +87,12 @@ func TestAccDefaultRoles_mariadbRejectsMultiple(t *testing.T) {
 
 // skipIfMariaDB reports whether the server is MariaDB, to skip MySQL-only steps.
 func skipIfMariaDB() (bool, error) {
-	ctx := context.Background()
-	db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+	serverInfo, err := getServerInfoFromMeta(context.Background(), testAccProvider.Meta())
 	if err != nil {
 		return false, err
 	}
 
-	return serverMariaDB(db)
+	return serverInfo.Flavor == FlavorMariaDB, nil
 }
 
 func testAccDefaultRoles(rn string, roles ...string) resource.TestCheckFunc {
@@ -113,7 +112,7 @@ func testAccDefaultRoles(rn string, roles ...string) resource.TestCheckFunc {
 			return err
 		}
 
-		isMariaDB, err := serverMariaDB(db)
+		isMariaDB, err := skipIfMariaDB()
 		if err != nil {
 			return err
 		}
@@ -165,7 +164,7 @@ func testAccDefaultRolesCheckDestroy(s *terraform.State) error {
 		return err
 	}
 
-	isMariaDB, err := serverMariaDB(db)
+	isMariaDB, err := skipIfMariaDB()
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,427 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// eventTimestampRegex matches the timestamps of schedules, which are read
+// back in this format.
+var eventTimestampRegex = regexp.MustCompile(`^\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}$`)
+
+// eventIntervalRegex matches the intervals of recurring events, like 1 DAY
+// or '1:30' HOUR_MINUTE.
+var eventIntervalRegex = regexp.MustCompile(`(?i)^\s*(\d+|'[^']*')\s+([a-z_]+)\s*$`)
+
+func resourceEvent() *schema.Resource {
+	timestamp := validation.StringMatch(eventTimestampRegex, "must be a timestamp like 2006-01-02 15:04:05")
+
+	return &schema.Resource{
+		CreateContext: CreateEvent,
+		UpdateContext: UpdateEvent,
+		ReadContext:   ReadEvent,
+		DeleteContext: DeleteEvent,
+		Importer: &schema.ResourceImporter{
+			StateContext: ImportEvent,
+		},
+
+		CustomizeDiff: customizeDiffEvent,
+
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"at": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"at", "every"},
+				ValidateFunc: timestamp,
+			},
+
+			"every": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ExactlyOneOf:     []string{"at", "every"},
+				ValidateFunc:     validation.StringMatch(eventIntervalRegex, "must be an interval like 1 DAY or '1:30' HOUR_MINUTE"),
+				DiffSuppressFunc: suppressEventIntervalDiff,
+			},
+
+			"starts": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"at"},
+				ValidateFunc:  timestamp,
+			},
+
+			"ends": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"at"},
+				ValidateFunc:  timestamp,
+			},
+
+			"on_completion_preserve": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"status": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "ENABLE",
+				ValidateFunc:     validation.StringInSlice([]string{"ENABLE", "DISABLE", "DISABLE ON SLAVE", "DISABLE ON REPLICA"}, true),
+				DiffSuppressFunc: suppressEventStatusDiff,
+			},
+
+			"comment": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "",
+			},
+
+			"definer": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ValidateFunc:     validateDefiner,
+				DiffSuppressFunc: suppressDefinerDiff,
+			},
+
+			"body": {
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: suppressProgramBodyDiff,
+			},
+		},
+	}
+}
+
+type eventDefinition struct {
+	Database             string
+	Name                 string
+	At                   string
+	Every                string
+	Starts               string
+	Ends                 string
+	OnCompletionPreserve bool
+	Status               string
+	Comment              string
+	Definer              string
+	Body                 string
+}
+
+// customizeDiffEvent logs when new events won't run, as the event scheduler
+// is off. Terraform Plugin SDK v2 doesn't let CustomizeDiff return warnings,
+// so the warning itself is only shown when the event is created.
+func customizeDiffEvent(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() != "" {
+		return nil
+	}
+
+	ctx = withLogSubsystem(ctx, logSubsystemSQL)
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		tflog.SubsystemWarn(ctx, logSubsystemSQL, "Failed connecting to check event_scheduler", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil
+	}
+	if warning := eventSchedulerWarning(ctx, db); warning != nil {
+		tflog.SubsystemWarn(ctx, logSubsystemSQL, warning[0].Summary, map[string]interface{}{
+			"detail": warning[0].Detail,
+		})
+	}
+	return nil
+}
+
+func CreateEvent(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if !db.Server.Capabilities.StoredPrograms {
+		return diag.Errorf("events are not supported by %s %s", db.Server.Flavor, db.Server.VersionString)
+	}
+
+	event := expandEvent(d)
+	stmtSQL := createEventSQL(event)
+	logSQL(ctx, stmtSQL)
+
+	_, err = db.ExecContext(ctx, stmtSQL)
+	if err != nil {
+		return diag.Errorf("failed creating event: %v", err)
+	}
+
+	d.SetId(databaseObjectID(event.Database, event.Name))
+
+	return ReadEvent(ctx, d, meta)
+}
+
+func UpdateEvent(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	stmtSQL := alterEventSQL(expandEvent(d))
+	logSQL(ctx, stmtSQL)
+
+	_, err = db.ExecContext(ctx, stmtSQL)
+	if err != nil {
+		return diag.Errorf("failed altering event: %v", err)
+	}
+
+	return ReadEvent(ctx, d, meta)
+}
+
+// ReadEvent reads the event, warning when the event scheduler is off, as
+// reads run for every plan and after CreateEvent.
+func ReadEvent(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	database, name, err := parseDatabaseObjectID(d.Id(), "event")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	event, err := readEvent(ctx, db, database, name)
+	if err != nil {
+		return diag.Errorf("failed reading event %s: %v", d.Id(), err)
+	}
+	if event == nil {
+		log.Printf("[WARN] Event (%s) not found; removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("database", event.Database)
+	d.Set("name", event.Name)
+	d.Set("at", event.At)
+	d.Set("every", event.Every)
+	d.Set("starts", event.Starts)
+	d.Set("ends", event.Ends)
+	d.Set("on_completion_preserve", event.OnCompletionPreserve)
+	d.Set("status", event.Status)
+	d.Set("comment", event.Comment)
+	d.Set("definer", event.Definer)
+	d.Set("body", event.Body)
+
+	return eventSchedulerWarning(ctx, db)
+}
+
+func DeleteEvent(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	stmtSQL := fmt.Sprintf("DROP EVENT %s.%s", quoteIdentifier(d.Get("database").(string)), quoteIdentifier(d.Get("name").(string)))
+	logSQL(ctx, stmtSQL)
+
+	_, err = db.ExecContext(ctx, stmtSQL)
+	if err != nil {
+		return diag.Errorf("failed dropping event: %v", err)
+	}
+
+	return nil
+}
+
+func ImportEvent(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	database, name, err := parseDatabaseObjectID(d.Id(), "event")
+	if err != nil {
+		return nil, err
+	}
+	d.Set("database", database)
+	d.Set("name", name)
+
+	diags := ReadEvent(ctx, d, meta)
+	if diags.HasError() {
+		return nil, fmt.Errorf("failed reading event: %v", diags)
+	}
+	if d.Id() == "" {
+		return nil, fmt.Errorf("event %s.%s not found", database, name)
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// eventSchedulerWarning warns when event_scheduler isn't ON, as events then
+// never run. Failing to look it up isn't an error, as it's only a hint. It's
+// looked up once per provider connection, so the warning isn't repeated for
+// every event.
+func eventSchedulerWarning(ctx context.Context, db *StatementExecutor) diag.Diagnostics {
+	if !db.checkOnce("event_scheduler") {
+		return nil
+	}
+	_, value, err := readGlobalVariable(ctx, db, "event_scheduler")
+	if err != nil {
+		ctx = withLogSubsystem(ctx, logSubsystemSQL)
+		tflog.SubsystemWarn(ctx, logSubsystemSQL, "Failed reading event_scheduler", map[string]interface{}{
+			"error": err.Error(),
+		})
+		return nil
+	}
+	if value == "" || strings.EqualFold(value, "ON") {
+		return nil
+	}
+	return diag.Diagnostics{{
+		Severity: diag.Warning,
+		Summary:  "The event scheduler is not running",
+		Detail:   fmt.Sprintf("event_scheduler is %s, so events don't run until it's set to ON.", value),
+	}}
+}
+
+func expandEvent(d *schema.ResourceData) *eventDefinition {
+	return &eventDefinition{
+		Database:             d.Get("database").(string),
+		Name:                 d.Get("name").(string),
+		At:                   d.Get("at").(string),
+		Every:                d.Get("every").(string),
+		Starts:               d.Get("starts").(string),
+		Ends:                 d.Get("ends").(string),
+		OnCompletionPreserve: d.Get("on_completion_preserve").(bool),
+		Status:               strings.ToUpper(d.Get("status").(string)),
+		Comment:              d.Get("comment").(string),
+		Definer:              d.Get("definer").(string),
+		Body:                 d.Get("body").(string),
+	}
+}
+
+func createEventSQL(event *eventDefinition) string {
+	stmtSQL := "CREATE"
+	if event.Definer != "" {
+		stmtSQL += " DEFINER = " + formatDefiner(event.Definer)
+	}
+	stmtSQL += fmt.Sprintf(" EVENT %s.%s", quoteIdentifier(event.Database), quoteIdentifier(event.Name))
+	return stmtSQL + eventClausesSQL(event)
+}
+
+// alterEventSQL sets every clause, so the event matches the configuration
+// whatever changed.
+func alterEventSQL(event *eventDefinition) string {
+	stmtSQL := "ALTER"
+	if event.Definer != "" {
+		stmtSQL += " DEFINER = " + formatDefiner(event.Definer)
+	}
+	stmtSQL += fmt.Sprintf(" EVENT %s.%s", quoteIdentifier(event.Database), quoteIdentifier(event.Name))
+	return stmtSQL + eventClausesSQL(event)
+}
+
+func eventClausesSQL(event *eventDefinition) string {
+	var stmtSQL string
+	if event.At != "" {
+		stmtSQL += " ON SCHEDULE AT " + quoteString(event.At)
+	} else {
+		stmtSQL += " ON SCHEDULE EVERY " + strings.TrimSpace(event.Every)
+		if event.Starts != "" {
+			stmtSQL += " STARTS " + quoteString(event.Starts)
+		}
+		if event.Ends != "" {
+			stmtSQL += " ENDS " + quoteString(event.Ends)
+		}
+	}
+
+	if event.OnCompletionPreserve {
+		stmtSQL += " ON COMPLETION PRESERVE"
+	} else {
+		stmtSQL += " ON COMPLETION NOT PRESERVE"
+	}
+	if event.Status != "" {
+		stmtSQL += " " + event.Status
+	}
+	stmtSQL += " COMMENT " + quoteString(event.Comment)
+
+	return stmtSQL + " DO\n" + normalizeProgramBody(event.Body)
+}
+
+// readEvent reads the event from information_schema, or returns nil if it
+// doesn't exist.
+func readEvent(ctx context.Context, db *StatementExecutor, database, name string) (*eventDefinition, error) {
+	event := &eventDefinition{Database: database, Name: name}
+
+	stmtSQL := `SELECT DATE_FORMAT(EXECUTE_AT, '%Y-%m-%d %H:%i:%s'), INTERVAL_VALUE, INTERVAL_FIELD,
+	DATE_FORMAT(STARTS, '%Y-%m-%d %H:%i:%s'), DATE_FORMAT(ENDS, '%Y-%m-%d %H:%i:%s'),
+	ON_COMPLETION, STATUS, EVENT_COMMENT, DEFINER, EVENT_DEFINITION
+FROM information_schema.EVENTS
+WHERE EVENT_SCHEMA = ? AND EVENT_NAME = ?`
+	logSQL(ctx, stmtSQL)
+
+	var at, intervalValue, intervalField, starts, ends sql.NullString
+	var onCompletion, status string
+	err := db.QueryRowContext(ctx, stmtSQL, database, name).Scan(&at, &intervalValue, &intervalField, &starts, &ends, &onCompletion, &status, &event.Comment, &event.Definer, &event.Body)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	event.At = at.String
+	if intervalValue.Valid {
+		event.Every = formatEventInterval(intervalValue.String, intervalField.String)
+		event.Starts = starts.String
+		event.Ends = ends.String
+	}
+	event.OnCompletionPreserve = onCompletion == "PRESERVE"
+	switch status {
+	case "ENABLED":
+		event.Status = "ENABLE"
+	case "DISABLED":
+		event.Status = "DISABLE"
+	default:
+		// SLAVESIDE_DISABLED, or REPLICA_SIDE_DISABLED since MySQL 8.0.22.
+		event.Status = "DISABLE ON SLAVE"
+	}
+
+	return event, nil
+}
+
+// formatEventInterval formats the interval of a recurring event the way it's
+// configured, quoting values that aren't a plain number.
+func formatEventInterval(value, field string) string {
+	value = strings.Trim(value, "'")
+	if _, err := strconv.ParseUint(value, 10, 64); err != nil {
+		value = quoteString(value)
+	}
+	return fmt.Sprintf("%s %s", value, strings.ToUpper(field))
+}
+
+func suppressEventIntervalDiff(k, old, new string, d *schema.ResourceData) bool {
+	oldMatch, newMatch := eventIntervalRegex.FindStringSubmatch(old), eventIntervalRegex.FindStringSubmatch(new)
+	if oldMatch == nil || newMatch == nil {
+		return false
+	}
+	return formatEventInterval(oldMatch[1], oldMatch[2]) == formatEventInterval(newMatch[1], newMatch[2])
+}
+
+func suppressEventStatusDiff(k, old, new string, d *schema.ResourceData) bool {
+	normalize := func(status string) string {
+		return strings.Replace(strings.ToUpper(status), "REPLICA", "SLAVE", 1)
+	}
+	return normalize(old) == normalize(new)
+}
@@ -0,0 +1,156 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccEvent_basic(t *testing.T) {
+	dbName := "tf_event_test"
+	resourceName := "mysql_event.purge"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipTiDB(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccEventCheckDestroy(dbName, "purge"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccEventConfig(dbName, "1 DAY", "ENABLE"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccEventExists(dbName, "purge"),
+					resource.TestCheckResourceAttr(resourceName, "id", dbName+".purge"),
+					resource.TestCheckResourceAttr(resourceName, "every", "1 DAY"),
+					resource.TestCheckResourceAttr(resourceName, "starts", "2030-01-01 03:00:00"),
+					resource.TestCheckResourceAttr(resourceName, "status", "ENABLE"),
+					resource.TestCheckResourceAttrSet(resourceName, "definer"),
+				),
+			},
+			{
+				Config: testAccEventConfig(dbName, "'1:30' HOUR_MINUTE", "DISABLE"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccEventExists(dbName, "purge"),
+					resource.TestCheckResourceAttr(resourceName, "every", "'1:30' HOUR_MINUTE"),
+					resource.TestCheckResourceAttr(resourceName, "status", "DISABLE"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateId:     dbName + ".purge",
+			},
+		},
+	})
+}
+
+func TestEventSQL(t *testing.T) {
+	testCases := map[string]struct {
+		event    eventDefinition
+		alter    bool
+		expected string
+	}{
+		"one time": {
+			eventDefinition{Database: "shop", Name: "once", At: "2030-01-01 00:00:00", OnCompletionPreserve: true, Status: "ENABLE", Body: "DELETE FROM carts;"},
+			false,
+			"CREATE EVENT `shop`.`once` ON SCHEDULE AT '2030-01-01 00:00:00' ON COMPLETION PRESERVE ENABLE COMMENT '' DO\nDELETE FROM carts",
+		},
+		"recurring": {
+			eventDefinition{Database: "shop", Name: "purge", Every: "1 DAY", Starts: "2030-01-01 03:00:00", Ends: "2031-01-01 00:00:00", Status: "DISABLE ON SLAVE", Comment: "Purges carts", Definer: "app@%", Body: "DELETE FROM carts"},
+			true,
+			"ALTER DEFINER = `app`@`%` EVENT `shop`.`purge` ON SCHEDULE EVERY 1 DAY STARTS '2030-01-01 03:00:00' ENDS '2031-01-01 00:00:00' ON COMPLETION NOT PRESERVE DISABLE ON SLAVE COMMENT 'Purges carts' DO\nDELETE FROM carts",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			stmt := createEventSQL(&tc.event)
+			if tc.alter {
+				stmt = alterEventSQL(&tc.event)
+			}
+			if stmt != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, stmt)
+			}
+		})
+	}
+}
+
+func TestSuppressEventIntervalDiff(t *testing.T) {
+	testCases := map[string]struct {
+		old, new string
+		expected bool
+	}{
+		"same":       {"1 DAY", "1 DAY", true},
+		"case":       {"1 DAY", "1 day", true},
+		"quoted":     {"1 DAY", "'1' DAY", true},
+		"composite":  {"'1:30' HOUR_MINUTE", "'1:30' hour_minute", true},
+		"value":      {"1 DAY", "2 DAY", false},
+		"field":      {"1 DAY", "1 HOUR", false},
+		"unparsable": {"1 DAY", "daily", false},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if suppress := suppressEventIntervalDiff("every", tc.old, tc.new, nil); suppress != tc.expected {
+				t.Errorf("expected %t, got %t", tc.expected, suppress)
+			}
+		})
+	}
+
+	if !suppressEventStatusDiff("status", "DISABLE ON SLAVE", "disable on replica", nil) {
+		t.Error("expected DISABLE ON REPLICA to match DISABLE ON SLAVE")
+	}
+}
+
+func testAccEventExists(dbName, name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		ctx := context.Background()
+		db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+		if err != nil {
+			return err
+		}
+
+		var count int
+		err = db.QueryRow("SELECT COUNT(*) FROM information_schema.EVENTS WHERE EVENT_SCHEMA = ? AND EVENT_NAME = ?", dbName, name).Scan(&count)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return fmt.Errorf("event %s.%s not found", dbName, name)
+		}
+		return nil
+	}
+}
+
+func testAccEventCheckDestroy(dbName, name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if err := testAccEventExists(dbName, name)(s); err == nil {
+			return fmt.Errorf("event %s.%s still exists", dbName, name)
+		}
+		return nil
+	}
+}
+
+func testAccEventConfig(dbName, every, status string) string {
+	return fmt.Sprintf(`
+resource "mysql_database" "test" {
+  name = "%s"
+}
+
+resource "mysql_event" "purge" {
+  database = mysql_database.test.name
+  name     = "purge"
+  every    = "%s"
+  starts   = "2030-01-01 03:00:00"
+  status   = "%s"
+  comment  = "Purges old sessions"
+  body     = "DELETE FROM sessions WHERE expires_at < NOW()"
+}
+`, dbName, every, status)
+}
@@ -63,7 +63,7 @@ func CreateOrUpdateGlobalVariable(ctx context.Context, d *schema.ResourceData, m
 		sqlCommand = fmt.Sprintf("%s'%s'", sqlBaseQuery, value)
 	}
 
-	log.Printf("[DEBUG] SQL: %s", sqlCommand)
+	logSQL(ctx, sqlCommand)
 
 	_, err = db.ExecContext(ctx, sqlCommand)
 	if err != nil {
@@ -81,15 +81,8 @@ func ReadGlobalVariable(ctx context.Context, d *schema.ResourceData, meta interf
 		return diag.FromErr(err)
 	}
 
-	stmt, err := db.Prepare("SHOW GLOBAL VARIABLES WHERE VARIABLE_NAME = ?")
+	name, value, err := readGlobalVariable(ctx, db, d.Id())
 	if err != nil {
-		return diag.Errorf("error during prepare statement for global variable: %s", err)
-	}
-
-	var name, value string
-	err = stmt.QueryRow(d.Id()).Scan(&name, &value)
-
-	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		d.SetId("")
 		return diag.Errorf("error during show global variables: %s", err)
 	}
@@ -100,6 +93,17 @@ func ReadGlobalVariable(ctx context.Context, d *schema.ResourceData, meta interf
 	return nil
 }
 
+// readGlobalVariable returns the name and global value of a variable, both
+// empty if it doesn't exist.
+func readGlobalVariable(ctx context.Context, db *StatementExecutor, variable string) (string, string, error) {
+	var name, value string
+	err := db.QueryRowContext(ctx, "SHOW GLOBAL VARIABLES WHERE VARIABLE_NAME = ?", variable).Scan(&name, &value)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", "", err
+	}
+	return name, value, nil
+}
+
 func DeleteGlobalVariable(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	db, err := getDatabaseFromMeta(ctx, meta)
 	if err != nil {
@@ -108,7 +112,7 @@ func DeleteGlobalVariable(ctx context.Context, d *schema.ResourceData, meta inte
 	name := d.Get("name").(string)
 
 	sqlCommand := fmt.Sprintf("SET GLOBAL %s = DEFAULT", quoteIdentifier(name))
-	log.Printf("[DEBUG] SQL: %s", sqlCommand)
+	logSQL(ctx, sqlCommand)
 
 	_, err = db.ExecContext(ctx, sqlCommand)
 	if err != nil {
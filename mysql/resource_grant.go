@@ -2,7 +2,6 @@ package mysql
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
 	"log"
 	"reflect"
@@ -12,7 +11,6 @@ import (
 	"strings"
 	"unicode"
 
-	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -392,11 +390,12 @@ func resourceGrant() *schema.Resource {
 }
 
 func supportsRoles(ctx context.Context, meta interface{}) (bool, error) {
-	currentVersion := getVersionFromMeta(ctx, meta)
+	serverInfo, err := getServerInfoFromMeta(ctx, meta)
+	if err != nil {
+		return false, err
+	}
 
-	requiredVersion, _ := version.NewVersion("8.0.0")
-	hasRoles := currentVersion.GreaterThan(requiredVersion)
-	return hasRoles, nil
+	return serverInfo.Capabilities.Roles, nil
 }
 
 var kReProcedureWithoutDatabase = regexp.MustCompile(`(?i)^(function|procedure) ([^.]*)$`)
@@ -522,7 +521,7 @@ func CreateGrant(ctx context.Context, d *schema.ResourceData, meta interface{})
 
 	stmtSQL := grant.SQLGrantStatement()
 
-	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	logSQL(ctx, stmtSQL)
 	_, err = db.ExecContext(ctx, stmtSQL)
 	if err != nil {
 		return diag.Errorf("Error running SQL (%v): %v", stmtSQL, err)
@@ -583,7 +582,7 @@ func UpdateGrant(ctx context.Context, d *schema.ResourceData, meta interface{})
 	return nil
 }
 
-func updatePrivileges(ctx context.Context, db *sql.DB, d *schema.ResourceData, grant MySQLGrant) error {
+func updatePrivileges(ctx context.Context, db *StatementExecutor, d *schema.ResourceData, grant MySQLGrant) error {
 	oldPrivsIf, newPrivsIf := d.GetChange("privileges")
 	oldPrivs := oldPrivsIf.(*schema.Set)
 	newPrivs := newPrivsIf.(*schema.Set)
@@ -611,7 +610,7 @@ func updatePrivileges(ctx context.Context, db *sql.DB, d *schema.ResourceData, g
 			return fmt.Errorf("grant does not support partial privilege revokes")
 		}
 		sqlCommand := partialRevoker.SQLPartialRevokePrivilegesStatement(privsToRevoke, revokeGrantOption)
-		log.Printf("[DEBUG] SQL for partial revoke: %s", sqlCommand)
+		logSQL(ctx, sqlCommand)
 
 		if _, err := db.ExecContext(ctx, sqlCommand); err != nil {
 			return err
@@ -621,7 +620,7 @@ func updatePrivileges(ctx context.Context, db *sql.DB, d *schema.ResourceData, g
 	// Do a full grant if anything has been added
 	if len(grantIfs) > 0 {
 		sqlCommand := grant.SQLGrantStatement()
-		log.Printf("[DEBUG] SQL to re-grant privileges: %s", sqlCommand)
+		logSQL(ctx, sqlCommand)
 
 		if _, err := db.ExecContext(ctx, sqlCommand); err != nil {
 			return err
@@ -648,7 +647,7 @@ func DeleteGrant(ctx context.Context, d *schema.ResourceData, meta interface{})
 	defer grantCreateMutex.Unlock(grant.GetUserOrRole().IDString())
 
 	sqlStatement := grant.SQLRevokeStatement()
-	log.Printf("[DEBUG] SQL to delete grant: %s", sqlStatement)
+	logSQL(ctx, sqlStatement)
 	_, err = db.ExecContext(ctx, sqlStatement)
 	if err != nil {
 		if !isNonExistingGrant(err) {
@@ -811,7 +810,7 @@ func combineGrants(grantA MySQLGrant, grantB MySQLGrant) (MySQLGrant, error) {
 	return nil, fmt.Errorf("unable to combine MySQLGrant %s of type %T with %s of type %T", grantA, grantA, grantB, grantB)
 }
 
-func getMatchingGrant(ctx context.Context, db *sql.DB, desiredGrant MySQLGrant) (MySQLGrant, error) {
+func getMatchingGrant(ctx context.Context, db *StatementExecutor, desiredGrant MySQLGrant) (MySQLGrant, error) {
 	allGrants, err := showUserGrants(ctx, db, desiredGrant.GetUserOrRole())
 	var result MySQLGrant
 	if err != nil {
@@ -991,16 +990,11 @@ func parseGrantFromRow(grantStr string) (MySQLGrant, error) {
 	}
 }
 
-func showUserGrants(ctx context.Context, db *sql.DB, userOrRole UserOrRole) ([]MySQLGrant, error) {
+func showUserGrants(ctx context.Context, db *StatementExecutor, userOrRole UserOrRole) ([]MySQLGrant, error) {
 	grants := []MySQLGrant{}
 
-	version, err := serverVersionString(db)
-	if err != nil {
-		return nil, fmt.Errorf("showUserGrants - getting server version failed: %w", err)
-	}
-
 	sqlStatement := fmt.Sprintf("SHOW GRANTS FOR %s", userOrRole.SQLString())
-	log.Printf("[DEBUG] SQL to show grants: %s", sqlStatement)
+	logSQL(ctx, sqlStatement)
 	rows, err := db.QueryContext(ctx, sqlStatement)
 
 	if isNonExistingGrant(err) {
@@ -1028,7 +1022,7 @@ func showUserGrants(ctx context.Context, db *sql.DB, userOrRole UserOrRole) ([]M
 			continue
 		}
 
-		if strings.HasSuffix(version, "-google") {
+		if db.Server.Platform == PlatformCloudSQL {
 			if roleGrant, ok := parsedGrant.(*RoleGrant); ok {
 				roles := roleGrant.GetRoles()
 				log.Printf("[DEBUG] Role names: %v", roles)
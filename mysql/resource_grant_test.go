@@ -651,7 +651,7 @@ func testAccPrivilege(rn string, privilege string, expectExists bool, expectGran
 		}
 
 		ctx := context.Background()
-		db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+		db, err := getDatabaseFromMeta(ctx, testAccProvider.Meta())
 		if err != nil {
 			return err
 		}
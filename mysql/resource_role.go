@@ -2,18 +2,37 @@ package mysql
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"log"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
+// defaultRoleHost is the host of roles created without one. Their ID is just
+// their name, as it was before roles had a host.
+const defaultRoleHost = "%"
+
 func resourceRole() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: CreateRole,
 		ReadContext:   ReadRole,
 		DeleteContext: DeleteRole,
+		Importer: &schema.ResourceImporter{
+			StateContext: ImportRole,
+		},
+
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceRoleV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceRoleStateUpgradeV0,
+			},
+		},
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -21,10 +40,106 @@ func resourceRole() *schema.Resource {
 				Required: true,
 				ForceNew: true,
 			},
+
+			"host": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				Default:  defaultRoleHost,
+			},
 		},
 	}
 }
 
+// resourceRoleV0 is the schema of roles before they had a host, when their
+// ID was their name.
+func resourceRoleV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+// resourceRoleStateUpgradeV0 rewrites the ID of roles to name@host, so
+// names containing @ aren't taken for a host.
+func resourceRoleStateUpgradeV0(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	name, _ := rawState["name"].(string)
+	if name == "" {
+		name, _ = rawState["id"].(string)
+	}
+	host, _ := rawState["host"].(string)
+	if host == "" {
+		host = defaultRoleHost
+	}
+
+	rawState["id"] = roleID(name, host)
+	rawState["name"] = name
+	rawState["host"] = host
+	return rawState, nil
+}
+
+// roleID returns the ID of the role name@host.
+func roleID(name, host string) string {
+	if host == defaultRoleHost && !strings.Contains(name, "@") {
+		return name
+	}
+	return fmt.Sprintf("%s@%s", name, host)
+}
+
+// parseRoleID splits a role ID into its name and host. Role names may contain
+// @, so the host starts after the last one.
+func parseRoleID(id string) (string, string) {
+	i := strings.LastIndex(id, "@")
+	if i < 0 {
+		return id, defaultRoleHost
+	}
+	return id[:i], id[i+1:]
+}
+
+// formatRoleIdentifier quotes a role for statements. MariaDB roles have no
+// host.
+func formatRoleIdentifier(db *StatementExecutor, name, host string) string {
+	if db.Server.Flavor == FlavorMariaDB {
+		return quoteIdentifier(name)
+	}
+	return formatUserIdentifier(name, host)
+}
+
+func checkRoleHost(db *StatementExecutor, host string) error {
+	if db.Server.Flavor == FlavorMariaDB && host != defaultRoleHost {
+		return fmt.Errorf("MariaDB roles have no host, got %q", host)
+	}
+	return nil
+}
+
+// readRole tells whether the role exists and whether the account found is a
+// role rather than a user. MySQL creates roles as locked accounts with an
+// expired password, MariaDB marks them with is_role.
+func readRole(ctx context.Context, db *StatementExecutor, name, host string) (bool, bool, error) {
+	stmtSQL := "SELECT account_locked = 'Y' AND password_expired = 'Y' FROM mysql.user WHERE user = ? AND host = ?"
+	args := []interface{}{name, host}
+	if db.Server.Flavor == FlavorMariaDB {
+		stmtSQL = "SELECT is_role = 'Y' FROM mysql.user WHERE user = ? AND host = ''"
+		args = args[:1]
+	}
+	logSQL(ctx, stmtSQL)
+
+	var isRole bool
+	err := db.QueryRowContext(ctx, stmtSQL, args...).Scan(&isRole)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, false, nil
+	}
+	if err != nil {
+		return false, false, err
+	}
+	return true, isRole, nil
+}
+
 func CreateRole(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	db, err := getDatabaseFromMeta(ctx, meta)
 	if err != nil {
@@ -32,16 +147,20 @@ func CreateRole(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 	}
 
 	roleName := d.Get("name").(string)
+	host := d.Get("host").(string)
+	if err := checkRoleHost(db, host); err != nil {
+		return diag.FromErr(err)
+	}
 
-	sql := fmt.Sprintf("CREATE ROLE '%s'", roleName)
-	log.Printf("[DEBUG] SQL: %s", sql)
+	sql := "CREATE ROLE " + formatRoleIdentifier(db, roleName, host)
+	logSQL(ctx, sql)
 
 	_, err = db.ExecContext(ctx, sql)
 	if err != nil {
 		return diag.Errorf("error creating role: %s", err)
 	}
 
-	d.SetId(roleName)
+	d.SetId(roleID(roleName, host))
 
 	return nil
 }
@@ -52,17 +171,19 @@ func ReadRole(ctx context.Context, d *schema.ResourceData, meta interface{}) dia
 		return diag.FromErr(err)
 	}
 
-	sql := fmt.Sprintf("SHOW GRANTS FOR '%s'", d.Id())
-	log.Printf("[DEBUG] SQL: %s", sql)
-
-	_, err = db.ExecContext(ctx, sql)
+	roleName, host := parseRoleID(d.Id())
+	found, _, err := readRole(ctx, db, roleName, host)
 	if err != nil {
+		return diag.Errorf("error reading role %s: %s", d.Id(), err)
+	}
+	if !found {
 		log.Printf("[WARN] Role (%s) not found; removing from state", d.Id())
 		d.SetId("")
 		return nil
 	}
 
-	d.Set("name", d.Id())
+	d.Set("name", roleName)
+	d.Set("host", host)
 
 	return nil
 }
@@ -73,8 +194,8 @@ func DeleteRole(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 		return diag.FromErr(err)
 	}
 
-	sql := fmt.Sprintf("DROP ROLE '%s'", d.Get("name").(string))
-	log.Printf("[DEBUG] SQL: %s", sql)
+	sql := "DROP ROLE " + formatRoleIdentifier(db, d.Get("name").(string), d.Get("host").(string))
+	logSQL(ctx, sql)
 
 	_, err = db.ExecContext(ctx, sql)
 	if err != nil {
@@ -83,3 +204,32 @@ func DeleteRole(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 
 	return nil
 }
+
+func ImportRole(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	roleName, host := parseRoleID(d.Id())
+	if err := checkRoleHost(db, host); err != nil {
+		return nil, err
+	}
+
+	found, isRole, err := readRole(ctx, db, roleName, host)
+	if err != nil {
+		return nil, fmt.Errorf("error reading role %s: %w", d.Id(), err)
+	}
+	if !found {
+		return nil, fmt.Errorf("role %s not found", d.Id())
+	}
+	if !isRole {
+		return nil, fmt.Errorf("%s is a user, not a role", d.Id())
+	}
+
+	d.SetId(roleID(roleName, host))
+	d.Set("name", roleName)
+	d.Set("host", host)
+
+	return []*schema.ResourceData{d}, nil
+}
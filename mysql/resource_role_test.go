@@ -2,11 +2,10 @@ package mysql
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
+	"regexp"
 	"testing"
 
-	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
@@ -19,87 +18,133 @@ func TestAccRole_basic(t *testing.T) {
 		PreCheck: func() {
 			testAccPreCheck(t)
 			testAccPreCheckSkipRds(t)
-			ctx := context.Background()
-			db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
-			if err != nil {
-				return
-			}
-
-			requiredVersion, _ := version.NewVersion("8.0.0")
-			currentVersion, err := serverVersion(db)
-			if err != nil {
-				return
-			}
-
-			if currentVersion.LessThan(requiredVersion) {
+			if !testAccServerInfo(t, "Roles").Capabilities.Roles {
 				t.Skip("Roles require MySQL 8+")
 			}
 		},
 		ProviderFactories: testAccProviderFactories,
-		CheckDestroy:      testAccRoleCheckDestroy(roleName),
+		CheckDestroy:      testAccRoleCheckDestroy(roleName, "%"),
 		Steps: []resource.TestStep{
 			{
 				Config: testAccRoleConfigBasic(roleName),
 				Check: resource.ComposeTestCheckFunc(
-					testAccRoleExists(roleName),
+					testAccRoleExists(roleName, "%"),
 					resource.TestCheckResourceAttr(resourceName, "name", roleName),
+					resource.TestCheckResourceAttr(resourceName, "host", "%"),
 				),
 			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateId:     roleName,
+			},
 		},
 	})
 }
 
-func testAccRoleExists(roleName string) resource.TestCheckFunc {
-	return func(s *terraform.State) error {
-		ctx := context.Background()
-		db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
-		if err != nil {
-			return err
-		}
+func TestAccRole_host(t *testing.T) {
+	roleName := "tf-test-role's"
+	resourceName := "mysql_role.test"
 
-		count, err := testAccGetRoleGrantCount(roleName, db)
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipRds(t)
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipNotMySQLVersionMin(t, "8.0.0")
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccRoleCheckDestroy(roleName, "example.com"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccRoleConfigHost(roleName, "example.com"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccRoleExists(roleName, "example.com"),
+					resource.TestCheckResourceAttr(resourceName, "id", roleName+"@example.com"),
+					resource.TestCheckResourceAttr(resourceName, "name", roleName),
+					resource.TestCheckResourceAttr(resourceName, "host", "example.com"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateId:     roleName + "@example.com",
+			},
+			{
+				ResourceName:  resourceName,
+				ImportState:   true,
+				ImportStateId: roleName + "@missing.example.com",
+				ExpectError:   regexp.MustCompile("not found"),
+			},
+		},
+	})
+}
 
+func TestRoleID(t *testing.T) {
+	testCases := map[string]struct {
+		name string
+		host string
+		id   string
+	}{
+		"default host":     {"developer", "%", "developer"},
+		"host":             {"developer", "10.0.0.%", "developer@10.0.0.%"},
+		"at in name":       {"dev@ops", "%", "dev@ops@%"},
+		"at in name, host": {"dev@ops", "localhost", "dev@ops@localhost"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			id := roleID(tc.name, tc.host)
+			if id != tc.id {
+				t.Errorf("expected ID %q, got %q", tc.id, id)
+			}
+			roleName, host := parseRoleID(id)
+			if roleName != tc.name || host != tc.host {
+				t.Errorf("expected %q and %q, got %q and %q", tc.name, tc.host, roleName, host)
+			}
+		})
+	}
+}
+
+func testAccRoleExists(roleName, host string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		exists, err := testAccRoleFound(roleName, host)
 		if err != nil {
 			return err
 		}
-
-		if count > 0 {
-			return nil
+		if !exists {
+			return fmt.Errorf("role %s@%s not found", roleName, host)
 		}
-
-		return fmt.Errorf("no grants found for role %s", roleName)
+		return nil
 	}
 }
 
-func testAccGetRoleGrantCount(roleName string, db *sql.DB) (int, error) {
-	rows, err := db.Query(fmt.Sprintf("SHOW GRANTS FOR '%s'", roleName))
+func testAccRoleFound(roleName, host string) (bool, error) {
+	ctx := context.Background()
+	db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
 	if err != nil {
-		return 0, err
+		return false, err
 	}
 
-	defer rows.Close()
-
-	count := 0
-	for rows.Next() {
-		count++
+	var count int
+	err = db.QueryRow("SELECT COUNT(*) FROM mysql.user WHERE user = ? AND host IN (?, '')", roleName, host).Scan(&count)
+	if err != nil {
+		return false, err
 	}
-
-	return count, nil
+	return count > 0, nil
 }
 
-func testAccRoleCheckDestroy(roleName string) resource.TestCheckFunc {
+func testAccRoleCheckDestroy(roleName, host string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
-		ctx := context.Background()
-		db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+		exists, err := testAccRoleFound(roleName, host)
 		if err != nil {
 			return err
 		}
-
-		count, err := testAccGetRoleGrantCount(roleName, db)
-		if count > 0 {
-			return fmt.Errorf("role %s still has grants/exists", roleName)
+		if exists {
+			return fmt.Errorf("role %s@%s still exists", roleName, host)
 		}
-
 		return nil
 	}
 }
@@ -111,3 +156,38 @@ resource "mysql_role" "test" {
 }
 `, roleName)
 }
+
+func testAccRoleConfigHost(roleName, host string) string {
+	return fmt.Sprintf(`
+resource "mysql_role" "test" {
+  name = "%s"
+  host = "%s"
+}
+`, roleName, host)
+}
+
+func TestResourceRoleStateUpgradeV0(t *testing.T) {
+	testCases := map[string]struct {
+		rawState   map[string]interface{}
+		expectedID string
+	}{
+		"name":         {map[string]interface{}{"id": "dev", "name": "dev"}, "dev"},
+		"name with at": {map[string]interface{}{"id": "dev@ops", "name": "dev@ops"}, "dev@ops@%"},
+		"host":         {map[string]interface{}{"id": "dev@10.0.0.1", "name": "dev", "host": "10.0.0.1"}, "dev@10.0.0.1"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			rawState, err := resourceRoleStateUpgradeV0(context.Background(), tc.rawState, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rawState["id"] != tc.expectedID {
+				t.Errorf("expected ID %q, got %q", tc.expectedID, rawState["id"])
+			}
+			if roleName, _ := parseRoleID(tc.expectedID); roleName != rawState["name"] {
+				t.Errorf("expected ID %q to parse back to name %q, got %q", tc.expectedID, rawState["name"], roleName)
+			}
+		})
+	}
+}
@@ -0,0 +1,478 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	routineTypeProcedure = "PROCEDURE"
+	routineTypeFunction  = "FUNCTION"
+)
+
+func resourceProcedure() *schema.Resource {
+	return resourceRoutine(routineTypeProcedure)
+}
+
+func resourceFunction() *schema.Resource {
+	return resourceRoutine(routineTypeFunction)
+}
+
+// resourceRoutine returns the resource of stored procedures or functions,
+// which only differ in parameter modes and the return type.
+func resourceRoutine(routineType string) *schema.Resource {
+	parameter := map[string]*schema.Schema{
+		"name": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"type": {
+			Type:             schema.TypeString,
+			Required:         true,
+			DiffSuppressFunc: suppressRoutineTypeDiff,
+		},
+	}
+	if routineType == routineTypeProcedure {
+		parameter["mode"] = &schema.Schema{
+			Type:             schema.TypeString,
+			Optional:         true,
+			Default:          "IN",
+			ValidateFunc:     validation.StringInSlice([]string{"IN", "OUT", "INOUT"}, true),
+			DiffSuppressFunc: suppressCaseDiff,
+		}
+	}
+
+	r := &schema.Resource{
+		CreateContext: createRoutine(routineType),
+		UpdateContext: updateRoutine(routineType),
+		ReadContext:   readRoutine(routineType),
+		DeleteContext: deleteRoutine(routineType),
+		Importer: &schema.ResourceImporter{
+			StateContext: importRoutine(routineType),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"parameter": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Resource{Schema: parameter},
+			},
+
+			"body": {
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: suppressProgramBodyDiff,
+			},
+
+			"deterministic": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"sql_data_access": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "CONTAINS SQL",
+				ValidateFunc:     validation.StringInSlice([]string{"CONTAINS SQL", "NO SQL", "READS SQL DATA", "MODIFIES SQL DATA"}, true),
+				DiffSuppressFunc: suppressCaseDiff,
+			},
+
+			"sql_security": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "DEFINER",
+				ValidateFunc:     validation.StringInSlice([]string{"DEFINER", "INVOKER"}, true),
+				DiffSuppressFunc: suppressCaseDiff,
+			},
+
+			"definer": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ValidateFunc:     validateDefiner,
+				DiffSuppressFunc: suppressDefinerDiff,
+			},
+
+			"comment": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "",
+			},
+		},
+	}
+	if routineType == routineTypeFunction {
+		r.Schema["returns"] = &schema.Schema{
+			Type:             schema.TypeString,
+			Required:         true,
+			DiffSuppressFunc: suppressRoutineTypeDiff,
+		}
+	}
+	return r
+}
+
+type routineParameter struct {
+	Mode string
+	Name string
+	Type string
+}
+
+type routineDefinition struct {
+	Type          string
+	Database      string
+	Name          string
+	Parameters    []routineParameter
+	Returns       string
+	Body          string
+	Deterministic bool
+	SQLDataAccess string
+	SQLSecurity   string
+	Definer       string
+	Comment       string
+}
+
+func createRoutine(routineType string) schema.CreateContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		db, err := getDatabaseFromMeta(ctx, meta)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if !db.Server.Capabilities.StoredPrograms {
+			return diag.Errorf("stored %ss are not supported by %s %s", strings.ToLower(routineType), db.Server.Flavor, db.Server.VersionString)
+		}
+
+		routine := expandRoutine(d, routineType)
+		stmtSQL := createRoutineSQL(routine)
+		logSQL(ctx, stmtSQL)
+
+		_, err = db.ExecContext(ctx, stmtSQL)
+		if err != nil {
+			return diag.Errorf("failed creating %s: %v", strings.ToLower(routineType), err)
+		}
+
+		d.SetId(databaseObjectID(routine.Database, routine.Name))
+
+		return readRoutine(routineType)(ctx, d, meta)
+	}
+}
+
+// updateRoutine changes the comment and SQL security in place. Any other
+// change replaces the routine, as ALTER can't change it.
+func updateRoutine(routineType string) schema.UpdateContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		db, err := getDatabaseFromMeta(ctx, meta)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		replaced := []string{"parameter", "body", "deterministic", "sql_data_access", "definer"}
+		if routineType == routineTypeFunction {
+			replaced = append(replaced, "returns")
+		}
+
+		routine := expandRoutine(d, routineType)
+		if !d.HasChanges(replaced...) {
+			stmtSQL := fmt.Sprintf("ALTER %s %s.%s COMMENT %s SQL SECURITY %s", routineType, quoteIdentifier(routine.Database), quoteIdentifier(routine.Name), quoteString(routine.Comment), routine.SQLSecurity)
+			logSQL(ctx, stmtSQL)
+
+			_, err = db.ExecContext(ctx, stmtSQL)
+			if err != nil {
+				return diag.Errorf("failed altering %s: %v", strings.ToLower(routineType), err)
+			}
+			return readRoutine(routineType)(ctx, d, meta)
+		}
+
+		previous, err := readRoutineDefinition(ctx, db, routineType, routine.Database, routine.Name)
+		if err != nil {
+			return diag.Errorf("failed reading %s %s: %v", strings.ToLower(routineType), d.Id(), err)
+		}
+		err = replaceRoutine(ctx, db, routine, previous)
+		if err != nil {
+			return diag.Errorf("failed replacing %s: %v", strings.ToLower(routineType), err)
+		}
+
+		return readRoutine(routineType)(ctx, d, meta)
+	}
+}
+
+func readRoutine(routineType string) schema.ReadContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		db, err := getDatabaseFromMeta(ctx, meta)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		database, name, err := parseDatabaseObjectID(d.Id(), strings.ToLower(routineType))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		routine, err := readRoutineDefinition(ctx, db, routineType, database, name)
+		if err != nil {
+			return diag.Errorf("failed reading %s %s: %v", strings.ToLower(routineType), d.Id(), err)
+		}
+		if routine == nil {
+			log.Printf("[WARN] %s (%s) not found; removing from state", routineType, d.Id())
+			d.SetId("")
+			return nil
+		}
+
+		d.Set("database", routine.Database)
+		d.Set("name", routine.Name)
+		d.Set("parameter", flattenRoutineParameters(routine.Parameters, routineType))
+		if routineType == routineTypeFunction {
+			d.Set("returns", routine.Returns)
+		}
+		// The body is only shown to the definer and users allowed to read
+		// all routines.
+		if routine.Body != "" {
+			d.Set("body", routine.Body)
+		}
+		d.Set("deterministic", routine.Deterministic)
+		d.Set("sql_data_access", routine.SQLDataAccess)
+		d.Set("sql_security", routine.SQLSecurity)
+		d.Set("definer", routine.Definer)
+		d.Set("comment", routine.Comment)
+
+		return nil
+	}
+}
+
+func deleteRoutine(routineType string) schema.DeleteContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		db, err := getDatabaseFromMeta(ctx, meta)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		stmtSQL := fmt.Sprintf("DROP %s %s.%s", routineType, quoteIdentifier(d.Get("database").(string)), quoteIdentifier(d.Get("name").(string)))
+		logSQL(ctx, stmtSQL)
+
+		_, err = db.ExecContext(ctx, stmtSQL)
+		if err != nil {
+			return diag.Errorf("failed dropping %s: %v", strings.ToLower(routineType), err)
+		}
+
+		return nil
+	}
+}
+
+func importRoutine(routineType string) schema.StateContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+		database, name, err := parseDatabaseObjectID(d.Id(), strings.ToLower(routineType))
+		if err != nil {
+			return nil, err
+		}
+		d.Set("database", database)
+		d.Set("name", name)
+
+		diags := readRoutine(routineType)(ctx, d, meta)
+		if diags.HasError() {
+			return nil, fmt.Errorf("failed reading %s: %v", strings.ToLower(routineType), diags)
+		}
+		if d.Id() == "" {
+			return nil, fmt.Errorf("%s %s.%s not found", strings.ToLower(routineType), database, name)
+		}
+
+		return []*schema.ResourceData{d}, nil
+	}
+}
+
+// replaceRoutine drops and creates the routine again under a named lock, so
+// concurrent applies don't interleave. If creating it fails, the previous
+// definition is restored when it's known.
+func replaceRoutine(ctx context.Context, db *StatementExecutor, routine *routineDefinition, previous *routineDefinition) error {
+	lockName := databaseObjectLockName(strings.ToLower(routine.Type), routine.Database, routine.Name)
+	return db.withNamedLock(ctx, lockName, func(conn *StatementConn) error {
+		stmtSQL := fmt.Sprintf("DROP %s IF EXISTS %s.%s", routine.Type, quoteIdentifier(routine.Database), quoteIdentifier(routine.Name))
+		logSQL(ctx, stmtSQL)
+		if _, err := conn.ExecContext(ctx, stmtSQL); err != nil {
+			return err
+		}
+
+		stmtSQL = createRoutineSQL(routine)
+		logSQL(ctx, stmtSQL)
+		_, err := conn.ExecContext(ctx, stmtSQL)
+		if err == nil || previous == nil || previous.Body == "" {
+			return err
+		}
+
+		stmtSQL = createRoutineSQL(previous)
+		logSQL(ctx, stmtSQL)
+		if _, restoreErr := conn.ExecContext(ctx, stmtSQL); restoreErr != nil {
+			return fmt.Errorf("%w; restoring the previous definition failed too: %v", err, restoreErr)
+		}
+		return fmt.Errorf("%w; the previous definition was restored", err)
+	})
+}
+
+func expandRoutine(d *schema.ResourceData, routineType string) *routineDefinition {
+	routine := &routineDefinition{
+		Type:          routineType,
+		Database:      d.Get("database").(string),
+		Name:          d.Get("name").(string),
+		Body:          d.Get("body").(string),
+		Deterministic: d.Get("deterministic").(bool),
+		SQLDataAccess: strings.ToUpper(d.Get("sql_data_access").(string)),
+		SQLSecurity:   strings.ToUpper(d.Get("sql_security").(string)),
+		Definer:       d.Get("definer").(string),
+		Comment:       d.Get("comment").(string),
+	}
+	if routineType == routineTypeFunction {
+		routine.Returns = d.Get("returns").(string)
+	}
+
+	for _, p := range d.Get("parameter").([]interface{}) {
+		param := p.(map[string]interface{})
+		parameter := routineParameter{
+			Name: param["name"].(string),
+			Type: param["type"].(string),
+		}
+		if mode, ok := param["mode"]; ok {
+			parameter.Mode = strings.ToUpper(mode.(string))
+		}
+		routine.Parameters = append(routine.Parameters, parameter)
+	}
+	return routine
+}
+
+func flattenRoutineParameters(parameters []routineParameter, routineType string) []interface{} {
+	result := make([]interface{}, 0, len(parameters))
+	for _, parameter := range parameters {
+		param := map[string]interface{}{
+			"name": parameter.Name,
+			"type": parameter.Type,
+		}
+		if routineType == routineTypeProcedure {
+			param["mode"] = parameter.Mode
+		}
+		result = append(result, param)
+	}
+	return result
+}
+
+func createRoutineSQL(routine *routineDefinition) string {
+	stmtSQL := "CREATE"
+	if routine.Definer != "" {
+		stmtSQL += " DEFINER = " + formatDefiner(routine.Definer)
+	}
+
+	parameters := make([]string, len(routine.Parameters))
+	for i, parameter := range routine.Parameters {
+		parameters[i] = fmt.Sprintf("%s %s", quoteIdentifier(parameter.Name), parameter.Type)
+		if parameter.Mode != "" {
+			parameters[i] = parameter.Mode + " " + parameters[i]
+		}
+	}
+	stmtSQL += fmt.Sprintf(" %s %s.%s(%s)", routine.Type, quoteIdentifier(routine.Database), quoteIdentifier(routine.Name), strings.Join(parameters, ", "))
+	if routine.Type == routineTypeFunction {
+		stmtSQL += " RETURNS " + routine.Returns
+	}
+
+	if routine.Comment != "" {
+		stmtSQL += " COMMENT " + quoteString(routine.Comment)
+	}
+	if routine.Deterministic {
+		stmtSQL += " DETERMINISTIC"
+	} else {
+		stmtSQL += " NOT DETERMINISTIC"
+	}
+	if routine.SQLDataAccess != "" {
+		stmtSQL += " " + routine.SQLDataAccess
+	}
+	if routine.SQLSecurity != "" {
+		stmtSQL += " SQL SECURITY " + routine.SQLSecurity
+	}
+
+	return stmtSQL + "\n" + normalizeProgramBody(routine.Body)
+}
+
+// readRoutineDefinition reads the routine from information_schema, or
+// returns nil if it doesn't exist.
+func readRoutineDefinition(ctx context.Context, db *StatementExecutor, routineType, database, name string) (*routineDefinition, error) {
+	routine := &routineDefinition{Type: routineType, Database: database, Name: name}
+
+	stmtSQL := `SELECT ROUTINE_DEFINITION, DTD_IDENTIFIER, IS_DETERMINISTIC, SQL_DATA_ACCESS, SECURITY_TYPE, DEFINER, ROUTINE_COMMENT
+FROM information_schema.ROUTINES
+WHERE ROUTINE_SCHEMA = ? AND ROUTINE_NAME = ? AND ROUTINE_TYPE = ?`
+	logSQL(ctx, stmtSQL)
+
+	var body, returns sql.NullString
+	var deterministic string
+	err := db.QueryRowContext(ctx, stmtSQL, database, name, routineType).Scan(&body, &returns, &deterministic, &routine.SQLDataAccess, &routine.SQLSecurity, &routine.Definer, &routine.Comment)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	routine.Body = body.String
+	routine.Returns = returns.String
+	routine.Deterministic = deterministic == "YES"
+
+	stmtSQL = `SELECT PARAMETER_MODE, PARAMETER_NAME, DTD_IDENTIFIER
+FROM information_schema.PARAMETERS
+WHERE SPECIFIC_SCHEMA = ? AND SPECIFIC_NAME = ? AND ROUTINE_TYPE = ? AND ORDINAL_POSITION > 0
+ORDER BY ORDINAL_POSITION`
+	logSQL(ctx, stmtSQL)
+
+	rows, err := db.QueryContext(ctx, stmtSQL, database, name, routineType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var mode sql.NullString
+		var parameter routineParameter
+		if err := rows.Scan(&mode, &parameter.Name, &parameter.Type); err != nil {
+			return nil, err
+		}
+		parameter.Mode = mode.String
+		routine.Parameters = append(routine.Parameters, parameter)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return routine, nil
+}
+
+// normalizeProgramBody drops the whitespace around the body of a stored
+// program and the semicolon ending it, which the server doesn't store.
+func normalizeProgramBody(body string) string {
+	return strings.TrimSpace(strings.TrimRight(strings.TrimSpace(body), ";"))
+}
+
+func suppressProgramBodyDiff(k, old, new string, d *schema.ResourceData) bool {
+	return normalizeProgramBody(old) == normalizeProgramBody(new)
+}
+
+// routineTypeCharsetRegex matches the character set and collation of string
+// types, which the server may add to the types of parameters.
+var routineTypeCharsetRegex = regexp.MustCompile(`(?i)\s+(?:character\s+set|charset|collate)\s+\w+`)
+
+func suppressRoutineTypeDiff(k, old, new string, d *schema.ResourceData) bool {
+	return normalizeColumnType(routineTypeCharsetRegex.ReplaceAllString(old, "")) == normalizeColumnType(routineTypeCharsetRegex.ReplaceAllString(new, ""))
+}
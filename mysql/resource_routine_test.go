@@ -0,0 +1,264 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccProcedure_basic(t *testing.T) {
+	dbName := "tf_procedure_test"
+	resourceName := "mysql_procedure.archive"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipTiDB(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccRoutineCheckDestroy(routineTypeProcedure, dbName, "archive"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccProcedureConfig(dbName, "SELECT days", "Archives orders"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccRoutineExists(routineTypeProcedure, dbName, "archive"),
+					resource.TestCheckResourceAttr(resourceName, "id", dbName+".archive"),
+					resource.TestCheckResourceAttr(resourceName, "parameter.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "parameter.1.mode", "OUT"),
+					resource.TestCheckResourceAttr(resourceName, "comment", "Archives orders"),
+				),
+			},
+			{
+				// Only the comment changes, which is altered in place.
+				Config: testAccProcedureConfig(dbName, "SELECT days", "Archives old orders"),
+				Check:  resource.TestCheckResourceAttr(resourceName, "comment", "Archives old orders"),
+			},
+			{
+				Config: testAccProcedureConfig(dbName, "SELECT days * 2", "Archives old orders"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccRoutineExists(routineTypeProcedure, dbName, "archive"),
+					resource.TestCheckResourceAttr(resourceName, "body", "BEGIN\n  SET archived = (SELECT days * 2);\nEND"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateId:     dbName + ".archive",
+			},
+		},
+	})
+}
+
+func TestAccFunction_basic(t *testing.T) {
+	dbName := "tf_function_test"
+	resourceName := "mysql_function.add_tax"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipTiDB(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccRoutineCheckDestroy(routineTypeFunction, dbName, "add_tax"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccFunctionConfig(dbName, "decimal(10,2)", "RETURN price * 1.2"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccRoutineExists(routineTypeFunction, dbName, "add_tax"),
+					resource.TestCheckResourceAttr(resourceName, "returns", "decimal(10,2)"),
+					resource.TestCheckResourceAttr(resourceName, "deterministic", "true"),
+					resource.TestCheckResourceAttr(resourceName, "parameter.0.name", "price"),
+				),
+			},
+			{
+				Config: testAccFunctionConfig(dbName, "decimal(12,2)", "RETURN price * 1.25"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccRoutineExists(routineTypeFunction, dbName, "add_tax"),
+					resource.TestCheckResourceAttr(resourceName, "returns", "decimal(12,2)"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateId:     dbName + ".add_tax",
+			},
+		},
+	})
+}
+
+func TestCreateRoutineSQL(t *testing.T) {
+	testCases := map[string]struct {
+		routine  routineDefinition
+		expected string
+	}{
+		"procedure": {
+			routineDefinition{
+				Type: routineTypeProcedure, Database: "shop", Name: "archive",
+				Parameters: []routineParameter{{Mode: "IN", Name: "days", Type: "int"}, {Mode: "OUT", Name: "archived", Type: "int"}},
+				Body:       "BEGIN\n  SELECT days INTO archived;\nEND;\n", SQLDataAccess: "MODIFIES SQL DATA", SQLSecurity: "INVOKER", Comment: "Archives orders",
+			},
+			"CREATE PROCEDURE `shop`.`archive`(IN `days` int, OUT `archived` int) COMMENT 'Archives orders' NOT DETERMINISTIC MODIFIES SQL DATA SQL SECURITY INVOKER\nBEGIN\n  SELECT days INTO archived;\nEND",
+		},
+		"function": {
+			routineDefinition{
+				Type: routineTypeFunction, Database: "shop", Name: "add_tax",
+				Parameters: []routineParameter{{Name: "price", Type: "decimal(10,2)"}}, Returns: "decimal(10,2)",
+				Body: "RETURN price * 1.2", Deterministic: true, SQLDataAccess: "NO SQL", SQLSecurity: "DEFINER", Definer: "app@%",
+			},
+			"CREATE DEFINER = `app`@`%` FUNCTION `shop`.`add_tax`(`price` decimal(10,2)) RETURNS decimal(10,2) DETERMINISTIC NO SQL SQL SECURITY DEFINER\nRETURN price * 1.2",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if stmt := createRoutineSQL(&tc.routine); stmt != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, stmt)
+			}
+		})
+	}
+}
+
+func TestReplaceRoutine(t *testing.T) {
+	ctx := context.Background()
+	connector := &fakeConnector{readOnly: "1"}
+	pool := sql.OpenDB(connector)
+	defer pool.Close()
+
+	db := newStatementExecutor(ctx, pool, StatementOptions{})
+	routine := &routineDefinition{Type: routineTypeFunction, Database: "shop", Name: "one", Returns: "int", Body: "RETURN 1", Deterministic: true}
+	if err := replaceRoutine(ctx, db, routine, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{
+		"DROP FUNCTION IF EXISTS `shop`.`one`",
+		"CREATE FUNCTION `shop`.`one`() RETURNS int DETERMINISTIC\nRETURN 1",
+		"DO RELEASE_LOCK(?)",
+	}
+	if !reflect.DeepEqual(connector.executed, expected) {
+		t.Errorf("expected %q, got %q", expected, connector.executed)
+	}
+}
+
+func TestSuppressRoutineTypeDiff(t *testing.T) {
+	testCases := map[string]struct {
+		old, new string
+		expected bool
+	}{
+		"same":          {"int", "int", true},
+		"display width": {"int(11)", "INT", true},
+		"charset":       {"varchar(32) CHARSET utf8mb4", "varchar(32)", true},
+		"collation":     {"varchar(32) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin", "VARCHAR(32)", true},
+		"length":        {"varchar(32)", "varchar(64)", false},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if suppress := suppressRoutineTypeDiff("type", tc.old, tc.new, nil); suppress != tc.expected {
+				t.Errorf("expected %t, got %t", tc.expected, suppress)
+			}
+		})
+	}
+}
+
+func TestDatabaseObjectLockName(t *testing.T) {
+	if name := databaseObjectLockName("procedure", "shop", "archive"); name != "terraform:procedure:shop.archive" {
+		t.Errorf("unexpected lock name %s", name)
+	}
+
+	long := databaseObjectLockName("procedure", strings.Repeat("d", 64), strings.Repeat("n", 64))
+	if len(long) != maxLockNameLength || !strings.HasPrefix(long, "terraform:") {
+		t.Errorf("expected long names to be hashed, got %s", long)
+	}
+}
+
+func testAccRoutineExists(routineType, dbName, name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		ctx := context.Background()
+		db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+		if err != nil {
+			return err
+		}
+
+		var count int
+		err = db.QueryRow("SELECT COUNT(*) FROM information_schema.ROUTINES WHERE ROUTINE_SCHEMA = ? AND ROUTINE_NAME = ? AND ROUTINE_TYPE = ?", dbName, name, routineType).Scan(&count)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return fmt.Errorf("%s %s.%s not found", strings.ToLower(routineType), dbName, name)
+		}
+		return nil
+	}
+}
+
+func testAccRoutineCheckDestroy(routineType, dbName, name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if err := testAccRoutineExists(routineType, dbName, name)(s); err == nil {
+			return fmt.Errorf("%s %s.%s still exists", strings.ToLower(routineType), dbName, name)
+		}
+		return nil
+	}
+}
+
+func testAccProcedureConfig(dbName, query, comment string) string {
+	return fmt.Sprintf(`
+resource "mysql_database" "test" {
+  name = "%s"
+}
+
+resource "mysql_procedure" "archive" {
+  database = mysql_database.test.name
+  name     = "archive"
+  comment  = "%s"
+
+  parameter {
+    name = "days"
+    type = "int"
+  }
+
+  parameter {
+    name = "archived"
+    type = "int"
+    mode = "OUT"
+  }
+
+  body = <<-EOT
+    BEGIN
+      SET archived = (%s);
+    END
+  EOT
+}
+`, dbName, comment, query)
+}
+
+func testAccFunctionConfig(dbName, returns, body string) string {
+	return fmt.Sprintf(`
+resource "mysql_database" "test" {
+  name = "%s"
+}
+
+resource "mysql_function" "add_tax" {
+  database        = mysql_database.test.name
+  name            = "add_tax"
+  returns         = "%s"
+  deterministic   = true
+  sql_data_access = "NO SQL"
+
+  parameter {
+    name = "price"
+    type = "decimal(10,2)"
+  }
+
+  body = "%s"
+}
+`, dbName, returns, body)
+}
@@ -0,0 +1,360 @@
+package mysql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+const unknownTableErrCode = 1146
+
+func resourceSequence() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateSequence,
+		UpdateContext: UpdateSequence,
+		ReadContext:   ReadSequence,
+		DeleteContext: DeleteSequence,
+		Importer: &schema.ResourceImporter{
+			StateContext: ImportSequence,
+		},
+
+		CustomizeDiff: func(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+			return checkSequenceSupport(ctx, meta)
+		},
+
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"start": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+
+			"increment": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  1,
+			},
+
+			"min_value": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+
+			"max_value": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Computed: true,
+			},
+
+			"cache": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  1000,
+			},
+
+			"cycle": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+
+			"engine": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				DiffSuppressFunc: suppressCaseDiff,
+			},
+		},
+	}
+}
+
+type sequenceDefinition struct {
+	Database string
+	Name     string
+	// Start, MinValue and MaxValue are nil for the server's default.
+	Start     *int64
+	Increment int64
+	MinValue  *int64
+	MaxValue  *int64
+	Cache     int64
+	Cycle     bool
+	Engine    string
+}
+
+func checkSequenceSupport(ctx context.Context, meta interface{}) error {
+	serverInfo, err := getServerInfoFromMeta(ctx, meta)
+	if err != nil {
+		return err
+	}
+
+	if serverInfo.Flavor != FlavorMariaDB {
+		return fmt.Errorf("sequences are only supported on MariaDB 10.3+, not %s", serverInfo.Flavor)
+	}
+
+	if !serverInfo.Capabilities.Sequences {
+		return fmt.Errorf("sequences require MariaDB 10.3 or newer (current version: %s)", serverInfo.Version.String())
+	}
+
+	return nil
+}
+
+func CreateSequence(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	sequence := expandSequence(d, false)
+	stmtSQL := createSequenceSQL(sequence)
+	logSQL(ctx, stmtSQL)
+
+	_, err = db.ExecContext(ctx, stmtSQL)
+	if err != nil {
+		return diag.Errorf("failed creating sequence: %v", err)
+	}
+
+	d.SetId(databaseObjectID(sequence.Database, sequence.Name))
+
+	return ReadSequence(ctx, d, meta)
+}
+
+func UpdateSequence(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	stmtSQL := alterSequenceSQL(expandSequence(d, true))
+	logSQL(ctx, stmtSQL)
+
+	_, err = db.ExecContext(ctx, stmtSQL)
+	if err != nil {
+		return diag.Errorf("failed altering sequence: %v", err)
+	}
+
+	return ReadSequence(ctx, d, meta)
+}
+
+func ReadSequence(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	database, name, err := parseDatabaseObjectID(d.Id(), "sequence")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	stmtSQL := fmt.Sprintf("SHOW CREATE SEQUENCE %s.%s", quoteIdentifier(database), quoteIdentifier(name))
+	logSQL(ctx, stmtSQL)
+
+	var tableName, createSequence string
+	err = db.QueryRowContext(ctx, stmtSQL).Scan(&tableName, &createSequence)
+	if errNumber := mysqlErrorNumber(err); errNumber == unknownTableErrCode || errNumber == unknownDatabaseErrCode {
+		log.Printf("[WARN] Sequence (%s) not found; removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("failed reading sequence %s: %v", d.Id(), err)
+	}
+
+	sequence, err := parseCreateSequence(createSequence)
+	if err != nil {
+		return diag.Errorf("failed reading sequence %s: %v", d.Id(), err)
+	}
+
+	d.Set("database", database)
+	d.Set("name", name)
+	d.Set("start", *sequence.Start)
+	d.Set("increment", sequence.Increment)
+	d.Set("min_value", *sequence.MinValue)
+	d.Set("max_value", *sequence.MaxValue)
+	d.Set("cache", sequence.Cache)
+	d.Set("cycle", sequence.Cycle)
+	d.Set("engine", sequence.Engine)
+
+	return nil
+}
+
+func DeleteSequence(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	stmtSQL := fmt.Sprintf("DROP SEQUENCE %s.%s", quoteIdentifier(d.Get("database").(string)), quoteIdentifier(d.Get("name").(string)))
+	logSQL(ctx, stmtSQL)
+
+	_, err = db.ExecContext(ctx, stmtSQL)
+	if err != nil {
+		return diag.Errorf("failed dropping sequence: %v", err)
+	}
+
+	return nil
+}
+
+func ImportSequence(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if err := checkSequenceSupport(ctx, meta); err != nil {
+		return nil, err
+	}
+
+	database, name, err := parseDatabaseObjectID(d.Id(), "sequence")
+	if err != nil {
+		return nil, err
+	}
+	d.Set("database", database)
+	d.Set("name", name)
+
+	diags := ReadSequence(ctx, d, meta)
+	if diags.HasError() {
+		return nil, fmt.Errorf("failed reading sequence: %v", diags)
+	}
+	if d.Id() == "" {
+		return nil, fmt.Errorf("sequence %s.%s not found", database, name)
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// expandSequence reads the sequence from the resource data. When creating
+// it, start and the limits are only set if they are configured, so the
+// server derives them from the increment.
+func expandSequence(d *schema.ResourceData, alter bool) *sequenceDefinition {
+	sequence := &sequenceDefinition{
+		Database:  d.Get("database").(string),
+		Name:      d.Get("name").(string),
+		Increment: int64(d.Get("increment").(int)),
+		Cache:     int64(d.Get("cache").(int)),
+		Cycle:     d.Get("cycle").(bool),
+		Engine:    d.Get("engine").(string),
+	}
+
+	optional := func(key string) *int64 {
+		if !alter && d.GetRawConfig().GetAttr(key).IsNull() {
+			return nil
+		}
+		value := int64(d.Get(key).(int))
+		return &value
+	}
+	sequence.Start = optional("start")
+	sequence.MinValue = optional("min_value")
+	sequence.MaxValue = optional("max_value")
+
+	return sequence
+}
+
+func createSequenceSQL(sequence *sequenceDefinition) string {
+	stmtSQL := fmt.Sprintf("CREATE SEQUENCE %s.%s%s", quoteIdentifier(sequence.Database), quoteIdentifier(sequence.Name), sequenceOptionsSQL(sequence))
+	if sequence.Engine != "" {
+		stmtSQL += " ENGINE = " + sequence.Engine
+	}
+	return stmtSQL
+}
+
+// alterSequenceSQL sets every option, which doesn't restart the sequence.
+// The engine can't be altered.
+func alterSequenceSQL(sequence *sequenceDefinition) string {
+	return fmt.Sprintf("ALTER SEQUENCE %s.%s%s", quoteIdentifier(sequence.Database), quoteIdentifier(sequence.Name), sequenceOptionsSQL(sequence))
+}
+
+func sequenceOptionsSQL(sequence *sequenceDefinition) string {
+	var stmtSQL string
+	if sequence.Start != nil {
+		stmtSQL += fmt.Sprintf(" START WITH %d", *sequence.Start)
+	}
+	stmtSQL += fmt.Sprintf(" INCREMENT BY %d", sequence.Increment)
+	if sequence.MinValue != nil {
+		stmtSQL += fmt.Sprintf(" MINVALUE %d", *sequence.MinValue)
+	}
+	if sequence.MaxValue != nil {
+		stmtSQL += fmt.Sprintf(" MAXVALUE %d", *sequence.MaxValue)
+	}
+	if sequence.Cache > 0 {
+		stmtSQL += fmt.Sprintf(" CACHE %d", sequence.Cache)
+	} else {
+		stmtSQL += " NOCACHE"
+	}
+	if sequence.Cycle {
+		stmtSQL += " CYCLE"
+	} else {
+		stmtSQL += " NOCYCLE"
+	}
+	return stmtSQL
+}
+
+var (
+	sequenceStartRegex     = regexp.MustCompile(`(?i)\bstart with (-?\d+)`)
+	sequenceIncrementRegex = regexp.MustCompile(`(?i)\bincrement by (-?\d+)`)
+	sequenceMinValueRegex  = regexp.MustCompile(`(?i)\bminvalue (-?\d+)`)
+	sequenceMaxValueRegex  = regexp.MustCompile(`(?i)\bmaxvalue (-?\d+)`)
+	sequenceCacheRegex     = regexp.MustCompile(`(?i)\bcache (\d+)`)
+	sequenceNoCacheRegex   = regexp.MustCompile(`(?i)\bnocache\b`)
+	sequenceCycleRegex     = regexp.MustCompile(`(?i)\b(no)?cycle\b`)
+	sequenceEngineRegex    = regexp.MustCompile(`(?i)\bENGINE=(\w+)`)
+)
+
+// parseCreateSequence parses the output of SHOW CREATE SEQUENCE, like
+// CREATE SEQUENCE `s` start with 1 minvalue 1 maxvalue 9223372036854775806
+// increment by 1 cache 1000 nocycle ENGINE=InnoDB.
+func parseCreateSequence(createSequence string) (*sequenceDefinition, error) {
+	sequence := &sequenceDefinition{}
+
+	number := func(re *regexp.Regexp, name string) (int64, error) {
+		m := re.FindStringSubmatch(createSequence)
+		if m == nil {
+			return 0, fmt.Errorf("%s not found in %q", name, createSequence)
+		}
+		return strconv.ParseInt(m[1], 10, 64)
+	}
+
+	var errs []error
+	start, err := number(sequenceStartRegex, "start")
+	errs = append(errs, err)
+	sequence.Start = &start
+	sequence.Increment, err = number(sequenceIncrementRegex, "increment")
+	errs = append(errs, err)
+	minValue, err := number(sequenceMinValueRegex, "minvalue")
+	errs = append(errs, err)
+	sequence.MinValue = &minValue
+	maxValue, err := number(sequenceMaxValueRegex, "maxvalue")
+	errs = append(errs, err)
+	sequence.MaxValue = &maxValue
+	if !sequenceNoCacheRegex.MatchString(createSequence) {
+		sequence.Cache, err = number(sequenceCacheRegex, "cache")
+		errs = append(errs, err)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	if m := sequenceCycleRegex.FindStringSubmatch(createSequence); m != nil {
+		sequence.Cycle = m[1] == ""
+	}
+	if m := sequenceEngineRegex.FindStringSubmatch(createSequence); m != nil {
+		sequence.Engine = m[1]
+	}
+
+	return sequence, nil
+}
@@ -0,0 +1,149 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccSequence_basic(t *testing.T) {
+	dbName := "tf_sequence_test"
+	resourceName := "mysql_sequence.order_number"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckRequireMariaDB(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccSequenceCheckDestroy(dbName, "order_number"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSequenceConfig(dbName, 1, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccSequenceExists(dbName, "order_number"),
+					resource.TestCheckResourceAttr(resourceName, "id", dbName+".order_number"),
+					resource.TestCheckResourceAttr(resourceName, "start", "1000"),
+					resource.TestCheckResourceAttr(resourceName, "min_value", "1"),
+					resource.TestCheckResourceAttr(resourceName, "max_value", "9223372036854775806"),
+					resource.TestCheckResourceAttr(resourceName, "cache", "1000"),
+					resource.TestCheckResourceAttr(resourceName, "engine", "InnoDB"),
+				),
+			},
+			{
+				Config: testAccSequenceConfig(dbName, 10, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "increment", "10"),
+					resource.TestCheckResourceAttr(resourceName, "cycle", "true"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateId:     dbName + ".order_number",
+			},
+		},
+	})
+}
+
+func TestParseCreateSequence(t *testing.T) {
+	int64Ptr := func(v int64) *int64 { return &v }
+
+	testCases := map[string]struct {
+		createSequence string
+		expected       *sequenceDefinition
+	}{
+		"default": {
+			"CREATE SEQUENCE `s` start with 1 minvalue 1 maxvalue 9223372036854775806 increment by 1 cache 1000 nocycle ENGINE=InnoDB",
+			&sequenceDefinition{Start: int64Ptr(1), Increment: 1, MinValue: int64Ptr(1), MaxValue: int64Ptr(9223372036854775806), Cache: 1000, Engine: "InnoDB"},
+		},
+		"descending": {
+			"CREATE SEQUENCE `s` start with -1 minvalue -9223372036854775807 maxvalue -1 increment by -1 nocache cycle ENGINE=Aria",
+			&sequenceDefinition{Start: int64Ptr(-1), Increment: -1, MinValue: int64Ptr(-9223372036854775807), MaxValue: int64Ptr(-1), Cycle: true, Engine: "Aria"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			sequence, err := parseCreateSequence(tc.createSequence)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(sequence, tc.expected) {
+				t.Errorf("expected %+v, got %+v", tc.expected, sequence)
+			}
+		})
+	}
+
+	if _, err := parseCreateSequence("CREATE TABLE `t` (`id` int)"); err == nil {
+		t.Error("expected error for output that isn't a sequence")
+	}
+}
+
+func TestSequenceSQL(t *testing.T) {
+	start := int64(1000)
+	sequence := &sequenceDefinition{Database: "shop", Name: "order_number", Start: &start, Increment: 1, Cache: 0, Cycle: true, Engine: "InnoDB"}
+
+	expected := "CREATE SEQUENCE `shop`.`order_number` START WITH 1000 INCREMENT BY 1 NOCACHE CYCLE ENGINE = InnoDB"
+	if stmt := createSequenceSQL(sequence); stmt != expected {
+		t.Errorf("expected %q, got %q", expected, stmt)
+	}
+
+	maxValue := int64(999999)
+	sequence.MaxValue = &maxValue
+	sequence.Cache = 100
+	expected = "ALTER SEQUENCE `shop`.`order_number` START WITH 1000 INCREMENT BY 1 MAXVALUE 999999 CACHE 100 CYCLE"
+	if stmt := alterSequenceSQL(sequence); stmt != expected {
+		t.Errorf("expected %q, got %q", expected, stmt)
+	}
+}
+
+func testAccSequenceExists(dbName, name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		ctx := context.Background()
+		db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+		if err != nil {
+			return err
+		}
+
+		var count int
+		err = db.QueryRow("SELECT COUNT(*) FROM information_schema.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND TABLE_TYPE = 'SEQUENCE'", dbName, name).Scan(&count)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return fmt.Errorf("sequence %s.%s not found", dbName, name)
+		}
+		return nil
+	}
+}
+
+func testAccSequenceCheckDestroy(dbName, name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if err := testAccSequenceExists(dbName, name)(s); err == nil {
+			return fmt.Errorf("sequence %s.%s still exists", dbName, name)
+		}
+		return nil
+	}
+}
+
+func testAccSequenceConfig(dbName string, increment int, cycle bool) string {
+	return fmt.Sprintf(`
+resource "mysql_database" "test" {
+  name = "%s"
+}
+
+resource "mysql_sequence" "order_number" {
+  database  = mysql_database.test.name
+  name      = "order_number"
+  start     = 1000
+  increment = %d
+  cycle     = %t
+}
+`, dbName, increment, cycle)
+}
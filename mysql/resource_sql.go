@@ -2,17 +2,27 @@ package mysql
 
 import (
 	"context"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"database/sql"
+	"encoding/json"
+	"fmt"
 	"log"
+	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func resourceSql() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: CreateSql,
+		UpdateContext: UpdateSql,
 		ReadContext:   ReadSql,
 		DeleteContext: DeleteSql,
+		Importer: &schema.ResourceImporter{
+			StateContext: ImportSql,
+		},
+
+		CustomizeDiff: customizeDiffSql,
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -23,17 +33,83 @@ func resourceSql() *schema.Resource {
 			"create_sql": {
 				Type:     schema.TypeString,
 				Required: true,
-				ForceNew: true,
+			},
+			"update_sql": {
+				Type:     schema.TypeString,
+				Optional: true,
 			},
 			"delete_sql": {
 				Type:     schema.TypeString,
 				Required: true,
+			},
+			"read_sql": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"transaction": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"triggers": {
+				Type:     schema.TypeMap,
+				Optional: true,
 				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"read_result": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"drifted": {
+				Type:     schema.TypeBool,
+				Computed: true,
 			},
 		},
 	}
 }
 
+// customizeDiffSql decides how changes are applied: create_sql changes and
+// drift run update_sql in place, or replace the resource if there is none.
+// Resources that were imported only store create_sql the first time.
+func customizeDiffSql(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+
+	oldCreateSql, _ := d.GetChange("create_sql")
+	createSqlChanged := d.HasChange("create_sql") && oldCreateSql.(string) != ""
+	drifted := d.Get("drifted").(bool)
+	if drifted {
+		if err := d.SetNew("drifted", false); err != nil {
+			return err
+		}
+	}
+
+	if createSqlChanged || drifted || d.HasChange("read_sql") {
+		var err error
+		if d.Get("read_sql").(string) == "" {
+			err = d.SetNew("read_result", "")
+		} else {
+			err = d.SetNewComputed("read_result")
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if d.Get("update_sql").(string) != "" {
+		return nil
+	}
+	switch {
+	case createSqlChanged:
+		return d.ForceNew("create_sql")
+	case drifted:
+		return d.ForceNew("drifted")
+	}
+	return nil
+}
+
 func CreateSql(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	db, err := getDatabaseFromMeta(ctx, meta)
 	if err != nil {
@@ -42,19 +118,56 @@ func CreateSql(ctx context.Context, d *schema.ResourceData, meta interface{}) di
 	name := d.Get("name").(string)
 	createSql := d.Get("create_sql").(string)
 
-	log.Println("[DEBUG] Executing SQL", createSql)
-
-	_, err = db.ExecContext(ctx, createSql)
+	err = execSql(ctx, db, createSql, d.Get("transaction").(bool))
 	if err != nil {
 		return diag.Errorf("couldn't exec SQL: %v", err)
 	}
 
 	d.SetId(name)
 
-	return nil
+	return setSqlReadResult(ctx, db, d)
+}
+
+func UpdateSql(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	oldCreateSql, _ := d.GetChange("create_sql")
+	createSqlChanged := d.HasChange("create_sql") && oldCreateSql.(string) != ""
+	if createSqlChanged || d.HasChange("drifted") {
+		err = execSql(ctx, db, d.Get("update_sql").(string), d.Get("transaction").(bool))
+		if err != nil {
+			return diag.Errorf("failed to run update SQL: %v", err)
+		}
+	}
+
+	return setSqlReadResult(ctx, db, d)
 }
 
 func ReadSql(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	readSql := d.Get("read_sql").(string)
+	if readSql == "" {
+		return nil
+	}
+
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	result, err := querySqlResult(ctx, db, readSql)
+	if err != nil {
+		return diag.Errorf("failed to run read SQL: %v", err)
+	}
+
+	drifted := result != d.Get("read_result").(string)
+	if drifted {
+		log.Printf("[WARN] Result of read SQL of %s changed from %s to %s", d.Id(), d.Get("read_result").(string), result)
+	}
+	d.Set("drifted", drifted)
+
 	return nil
 }
 
@@ -65,9 +178,7 @@ func DeleteSql(ctx context.Context, d *schema.ResourceData, meta interface{}) di
 	}
 	deleteSql := d.Get("delete_sql").(string)
 
-	log.Println("[DEBUG] Executing SQL:", deleteSql)
-
-	_, err = db.ExecContext(ctx, deleteSql)
+	err = execSql(ctx, db, deleteSql, d.Get("transaction").(bool))
 	if err != nil {
 		return diag.Errorf("failed to run delete SQL: %v", err)
 	}
@@ -75,3 +186,143 @@ func DeleteSql(ctx context.Context, d *schema.ResourceData, meta interface{}) di
 	d.SetId("")
 	return nil
 }
+
+// ImportSql imports the resource by its name. As the statements can't be
+// read back, create_sql is stored without running it on the first apply.
+func ImportSql(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	d.Set("name", d.Id())
+	d.Set("drifted", false)
+	return []*schema.ResourceData{d}, nil
+}
+
+// setSqlReadResult stores the result of read_sql after it was applied, which
+// later reads compare theirs with.
+func setSqlReadResult(ctx context.Context, db *StatementExecutor, d *schema.ResourceData) diag.Diagnostics {
+	d.Set("drifted", false)
+
+	readSql := d.Get("read_sql").(string)
+	if readSql == "" {
+		d.Set("read_result", "")
+		return nil
+	}
+
+	result, err := querySqlResult(ctx, db, readSql)
+	if err != nil {
+		return diag.Errorf("failed to run read SQL: %v", err)
+	}
+	d.Set("read_result", result)
+
+	return nil
+}
+
+// execSql runs the statements of sqlText. Without a transaction, they are
+// sent as they are, so sqlText must be a single statement. In a transaction,
+// they are split at semicolons and rolled back if one fails.
+func execSql(ctx context.Context, db *StatementExecutor, sqlText string, transaction bool) error {
+	if !transaction {
+		logSQL(ctx, sqlText)
+		_, err := db.ExecContext(ctx, sqlText)
+		return err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, stmt := range splitSqlStatements(sqlText) {
+		logSQL(ctx, stmt)
+		if _, err := tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// splitSqlStatements splits sqlText at the semicolons that aren't quoted or
+// in comments. DELIMITER isn't supported, as it's a client command.
+func splitSqlStatements(sqlText string) []string {
+	var stmts []string
+	start := 0
+	add := func(end int) {
+		if stmt := strings.TrimSpace(sqlText[start:end]); stmt != "" {
+			stmts = append(stmts, stmt)
+		}
+	}
+
+	for i := 0; i < len(sqlText); i++ {
+		switch c := sqlText[i]; {
+		case c == '\'' || c == '"' || c == '`':
+			for i++; i < len(sqlText) && sqlText[i] != c; i++ {
+				if sqlText[i] == '\\' && c != '`' {
+					i++
+				}
+			}
+		case c == '#' || (c == '-' && strings.HasPrefix(sqlText[i:], "-- ")):
+			for i < len(sqlText) && sqlText[i] != '\n' {
+				i++
+			}
+		case c == '/' && strings.HasPrefix(sqlText[i:], "/*"):
+			if end := strings.Index(sqlText[i+2:], "*/"); end >= 0 {
+				i += end + 3
+			} else {
+				i = len(sqlText)
+			}
+		case c == ';':
+			add(i)
+			start = i + 1
+		}
+	}
+	add(len(sqlText))
+
+	return stmts
+}
+
+// querySqlResult runs query and encodes its rows as a JSON list of objects
+// from column names to values, null for NULL.
+func querySqlResult(ctx context.Context, db *StatementExecutor, query string) (string, error) {
+	logSQL(ctx, query)
+
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return "", err
+	}
+
+	result := make([]map[string]*string, 0)
+	for rows.Next() {
+		values := make([]sql.NullString, len(columns))
+		dest := make([]interface{}, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return "", err
+		}
+
+		row := make(map[string]*string, len(columns))
+		for i, column := range columns {
+			if values[i].Valid {
+				row[column] = &values[i].String
+			} else {
+				row[column] = nil
+			}
+		}
+		result = append(result, row)
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+
+	encoded, err := json.Marshal(result)
+	if err != nil {
+		return "", fmt.Errorf("failed encoding result: %w", err)
+	}
+	return string(encoded), nil
+}
@@ -0,0 +1,241 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccSql_basic(t *testing.T) {
+	dbName := "tf_sql_test"
+	resourceName := "mysql_sql.setting"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccDatabaseCheckDestroy(dbName),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccSqlConfig(dbName, "a"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccSqlSettingValue(dbName, "mode", "a"),
+					testAccSqlSettingValue(dbName, "level", "1"),
+					resource.TestCheckResourceAttr(resourceName, "read_result", `[{"name":"level","value":"1"},{"name":"mode","value":"a"}]`),
+					resource.TestCheckResourceAttr(resourceName, "drifted", "false"),
+				),
+			},
+			{
+				// Changing the setting outside of Terraform runs update_sql.
+				PreConfig: func() {
+					testAccSqlExec(t, fmt.Sprintf("UPDATE `%s`.`settings` SET `value` = 'drift' WHERE `name` = 'mode'", dbName))
+				},
+				Config: testAccSqlConfig(dbName, "a"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccSqlSettingValue(dbName, "mode", "a"),
+					resource.TestCheckResourceAttr(resourceName, "drifted", "false"),
+				),
+			},
+			{
+				Config: testAccSqlConfig(dbName, "b"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccSqlSettingValue(dbName, "mode", "b"),
+					testAccSqlSettingValue(dbName, "level", "1"),
+					resource.TestCheckResourceAttr(resourceName, "read_result", `[{"name":"level","value":"1"},{"name":"mode","value":"b"}]`),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateId:           "setting",
+				ImportStateVerifyIgnore: []string{"create_sql", "update_sql", "delete_sql", "read_sql", "read_result", "transaction"},
+			},
+		},
+	})
+}
+
+func TestResourceSql_Diff(t *testing.T) {
+	state := map[string]string{
+		"id":          "setting",
+		"name":        "setting",
+		"create_sql":  "INSERT INTO t VALUES (1)",
+		"delete_sql":  "DELETE FROM t",
+		"read_sql":    "SELECT * FROM t",
+		"read_result": `[{"a":"1"}]`,
+		"transaction": "false",
+		"drifted":     "false",
+	}
+	config := map[string]interface{}{
+		"name":       "setting",
+		"create_sql": "INSERT INTO t VALUES (1)",
+		"delete_sql": "DELETE FROM t",
+		"read_sql":   "SELECT * FROM t",
+	}
+
+	testCases := map[string]struct {
+		state       map[string]string
+		config      map[string]interface{}
+		changed     bool
+		requiresNew bool
+	}{
+		"unchanged":              {nil, nil, false, false},
+		"create_sql":             {nil, map[string]interface{}{"create_sql": "INSERT INTO t VALUES (2)"}, true, true},
+		"create_sql with update": {nil, map[string]interface{}{"create_sql": "INSERT INTO t VALUES (2)", "update_sql": "UPDATE t SET a = 2"}, true, false},
+		"drifted":                {map[string]string{"drifted": "true"}, nil, true, true},
+		"drifted with update":    {map[string]string{"drifted": "true", "update_sql": "UPDATE t SET a = 2"}, map[string]interface{}{"update_sql": "UPDATE t SET a = 2"}, true, false},
+		"delete_sql":             {nil, map[string]interface{}{"delete_sql": "TRUNCATE t"}, true, false},
+		"imported":               {map[string]string{"create_sql": "", "delete_sql": "", "read_sql": "", "read_result": ""}, nil, true, false},
+		"triggers":               {nil, map[string]interface{}{"triggers": map[string]interface{}{"version": "2"}}, true, true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			attributes := map[string]string{}
+			for k, v := range state {
+				attributes[k] = v
+			}
+			for k, v := range tc.state {
+				attributes[k] = v
+			}
+			raw := map[string]interface{}{}
+			for k, v := range config {
+				raw[k] = v
+			}
+			for k, v := range tc.config {
+				raw[k] = v
+			}
+
+			diff, err := resourceSql().Diff(context.Background(), &terraform.InstanceState{ID: "setting", Attributes: attributes}, terraform.NewResourceConfigRaw(raw), nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if changed := diff != nil && !diff.Empty(); changed != tc.changed {
+				t.Fatalf("expected changed to be %t, got diff %v", tc.changed, diff)
+			}
+			if tc.changed && diff.RequiresNew() != tc.requiresNew {
+				t.Errorf("expected requires new to be %t, got diff %v", tc.requiresNew, diff)
+			}
+		})
+	}
+}
+
+func TestSplitSqlStatements(t *testing.T) {
+	testCases := map[string]struct {
+		sql      string
+		expected []string
+	}{
+		"single":        {"SELECT 1", []string{"SELECT 1"}},
+		"trailing":      {"SELECT 1;\n", []string{"SELECT 1"}},
+		"multiple":      {"INSERT INTO t VALUES (1); INSERT INTO t VALUES (2);", []string{"INSERT INTO t VALUES (1)", "INSERT INTO t VALUES (2)"}},
+		"quoted":        {`INSERT INTO t VALUES ('a;b', "c;d"); SELECT ';'`, []string{`INSERT INTO t VALUES ('a;b', "c;d")`, `SELECT ';'`}},
+		"escaped quote": {`SELECT 'it\'s;'; SELECT 2`, []string{`SELECT 'it\'s;'`, "SELECT 2"}},
+		"identifier":    {"SELECT `a;b` FROM t; SELECT 2", []string{"SELECT `a;b` FROM t", "SELECT 2"}},
+		"comments":      {"SELECT 1; -- a; comment\n# another;\n/* and; one */ SELECT 2", []string{"SELECT 1", "-- a; comment\n# another;\n/* and; one */ SELECT 2"}},
+		"empty":         {" ; ;", nil},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			stmts := splitSqlStatements(tc.sql)
+			if !reflect.DeepEqual(stmts, tc.expected) {
+				t.Errorf("expected %q, got %q", tc.expected, stmts)
+			}
+		})
+	}
+}
+
+func TestExecSql_Transaction(t *testing.T) {
+	ctx := context.Background()
+	testCases := map[string]struct {
+		opts     StatementOptions
+		execErr  error
+		expected []string
+	}{
+		"commit":  {StatementOptions{}, nil, []string{"BEGIN", "INSERT INTO t VALUES (1)", "INSERT INTO t VALUES (2)", "COMMIT"}},
+		"failure": {StatementOptions{}, errors.New("duplicate entry"), []string{"BEGIN", "INSERT INTO t VALUES (1)", "ROLLBACK"}},
+		"dry run": {StatementOptions{DryRun: true}, nil, []string{"BEGIN", "ROLLBACK"}},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			connector := &fakeConnector{execErr: tc.execErr}
+			pool := sql.OpenDB(connector)
+			defer pool.Close()
+
+			db := newStatementExecutor(ctx, pool, tc.opts)
+			err := execSql(ctx, db, "INSERT INTO t VALUES (1); INSERT INTO t VALUES (2)", true)
+			if (err != nil) != (tc.execErr != nil) {
+				t.Errorf("expected error %v, got %v", tc.execErr, err)
+			}
+			if !reflect.DeepEqual(connector.executed, tc.expected) {
+				t.Errorf("expected %q, got %q", tc.expected, connector.executed)
+			}
+		})
+	}
+}
+
+func testAccSqlExec(t *testing.T, stmt string) {
+	ctx := context.Background()
+	db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+	if err != nil {
+		t.Fatalf("failed connecting: %v", err)
+	}
+	if _, err := db.Exec(stmt); err != nil {
+		t.Fatalf("failed running %s: %v", stmt, err)
+	}
+}
+
+func testAccSqlSettingValue(dbName, name, expected string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		ctx := context.Background()
+		db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+		if err != nil {
+			return err
+		}
+
+		var value string
+		err = db.QueryRow(fmt.Sprintf("SELECT `value` FROM `%s`.`settings` WHERE `name` = ?", dbName), name).Scan(&value)
+		if err != nil {
+			return fmt.Errorf("failed reading setting %s: %w", name, err)
+		}
+		if value != expected {
+			return fmt.Errorf("expected setting %s to be %q, got %q", name, expected, value)
+		}
+		return nil
+	}
+}
+
+func testAccSqlConfig(dbName, mode string) string {
+	return fmt.Sprintf(`
+resource "mysql_database" "test" {
+  name = "%[1]s"
+}
+
+resource "mysql_sql" "table" {
+  name       = "settings"
+  create_sql = "CREATE TABLE ${mysql_database.test.name}.settings (name VARCHAR(32) PRIMARY KEY, value VARCHAR(32))"
+  delete_sql = "DROP TABLE ${mysql_database.test.name}.settings"
+}
+
+locals {
+  table = "${mysql_database.test.name}.${mysql_sql.table.name}"
+}
+
+resource "mysql_sql" "setting" {
+  name        = "setting"
+  transaction = true
+  create_sql  = "INSERT INTO ${local.table} VALUES ('mode', '%[2]s'); INSERT INTO ${local.table} VALUES ('level', '1')"
+  update_sql  = "REPLACE INTO ${local.table} VALUES ('mode', '%[2]s')"
+  delete_sql  = "DELETE FROM ${local.table}"
+  read_sql    = "SELECT name, value FROM ${local.table} ORDER BY name"
+}
+`, dbName, mode)
+}
@@ -0,0 +1,1549 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var tableIndexTypes = []string{"INDEX", "UNIQUE", "FULLTEXT", "SPATIAL"}
+
+var tableReferentialActions = []string{"RESTRICT", "CASCADE", "SET NULL", "NO ACTION", "SET DEFAULT"}
+
+func resourceTable() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateTable,
+		UpdateContext: UpdateTable,
+		ReadContext:   ReadTable,
+		DeleteContext: DeleteTable,
+		Importer: &schema.ResourceImporter{
+			StateContext: ImportTable,
+		},
+
+		CustomizeDiff: customizeDiffTable,
+
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"column": {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"previous_name": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"type": {
+							Type:             schema.TypeString,
+							Required:         true,
+							DiffSuppressFunc: suppressColumnTypeDiff,
+						},
+						"nullable": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  true,
+						},
+						"default": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"default_expression": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							DiffSuppressFunc: suppressExpressionDiff,
+						},
+						"auto_increment": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
+						"character_set": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"collation": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"comment": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"generated_expression": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							DiffSuppressFunc: suppressExpressionDiff,
+						},
+						"generated_stored": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
+					},
+				},
+			},
+
+			"primary_key": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+
+			"index": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Set:      tableIndexHash,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"columns": {
+							Type:     schema.TypeList,
+							Required: true,
+							MinItems: 1,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"type": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							Default:          "INDEX",
+							ValidateFunc:     validation.StringInSlice(tableIndexTypes, true),
+							DiffSuppressFunc: suppressCaseDiff,
+						},
+						"invisible": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
+					},
+				},
+			},
+
+			"foreign_key": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Set:      tableForeignKeyHash,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"columns": {
+							Type:     schema.TypeList,
+							Required: true,
+							MinItems: 1,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"referenced_database": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"referenced_table": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"referenced_columns": {
+							Type:     schema.TypeList,
+							Required: true,
+							MinItems: 1,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"on_delete": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							ValidateFunc:     validation.StringInSlice(tableReferentialActions, true),
+							DiffSuppressFunc: suppressReferentialActionDiff,
+						},
+						"on_update": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							ValidateFunc:     validation.StringInSlice(tableReferentialActions, true),
+							DiffSuppressFunc: suppressReferentialActionDiff,
+						},
+					},
+				},
+			},
+
+			"check": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Set:      tableCheckHash,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"expression": {
+							Type:             schema.TypeString,
+							Required:         true,
+							DiffSuppressFunc: suppressExpressionDiff,
+						},
+					},
+				},
+			},
+
+			"engine": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				DiffSuppressFunc: suppressCaseDiff,
+			},
+
+			"row_format": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				DiffSuppressFunc: suppressCaseDiff,
+			},
+
+			"default_character_set": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+
+			"default_collation": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+
+			"comment": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+
+			"auto_increment": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+
+			"allow_dropping_data": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+		},
+	}
+}
+
+type tableColumn struct {
+	Name string
+	// PreviousName is the name the column is renamed from.
+	PreviousName string
+	Type         string
+	Nullable     bool
+	Default      string
+	// DefaultSet tells an empty Default configured as such from no default.
+	DefaultSet          bool
+	DefaultExpression   string
+	AutoIncrement       bool
+	CharacterSet        string
+	Collation           string
+	Comment             string
+	GeneratedExpression string
+	GeneratedStored     bool
+}
+
+type tableIndex struct {
+	Name      string
+	Type      string
+	Columns   []string
+	Invisible bool
+}
+
+type tableForeignKey struct {
+	Name               string
+	Columns            []string
+	ReferencedDatabase string
+	ReferencedTable    string
+	ReferencedColumns  []string
+	OnDelete           string
+	OnUpdate           string
+}
+
+type tableCheck struct {
+	Name       string
+	Expression string
+}
+
+// tableDefinition is a table as configured, or as read from
+// information_schema.
+type tableDefinition struct {
+	Database     string
+	Name         string
+	Columns      []tableColumn
+	PrimaryKey   []string
+	Indexes      []tableIndex
+	ForeignKeys  []tableForeignKey
+	Checks       []tableCheck
+	Engine       string
+	RowFormat    string
+	CharacterSet string
+	Collation    string
+	Comment      string
+	// AutoIncrement is only applied, as the server moves it on every insert.
+	AutoIncrement int
+}
+
+func (t *tableDefinition) identifier() string {
+	return fmt.Sprintf("%s.%s", quoteIdentifier(t.Database), quoteIdentifier(t.Name))
+}
+
+func CreateTable(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	table := expandTable(d, d.Get)
+	markConfiguredDefaults(table.Columns, d.GetRawConfig())
+	if err := checkTableSupport(db, table); err != nil {
+		return diag.FromErr(err)
+	}
+
+	stmtSQL := createTableSQL(table)
+	logSQL(ctx, stmtSQL)
+
+	_, err = db.ExecContext(ctx, stmtSQL)
+	if err != nil {
+		return diag.Errorf("failed creating table: %v", err)
+	}
+
+	d.SetId(databaseObjectID(table.Database, table.Name))
+
+	return ReadTable(ctx, d, meta)
+}
+
+func UpdateTable(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	oldTable := expandTable(d, func(key string) interface{} {
+		o, _ := d.GetChange(key)
+		return o
+	})
+	newTable := expandTable(d, d.Get)
+	markConfiguredDefaults(newTable.Columns, d.GetRawConfig())
+	if err := checkTableSupport(db, newTable); err != nil {
+		return diag.FromErr(err)
+	}
+
+	for _, stmtSQL := range alterTableSQL(oldTable, newTable, db.Server.Flavor) {
+		logSQL(ctx, stmtSQL)
+		_, err = db.ExecContext(ctx, stmtSQL)
+		if err != nil {
+			// Earlier statements may have been applied, so the state is
+			// read back from the server rather than taken from the config.
+			diags := diag.Errorf("failed altering table: %v", err)
+			return append(diags, ReadTable(ctx, d, meta)...)
+		}
+	}
+
+	return ReadTable(ctx, d, meta)
+}
+
+func ReadTable(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	database, name, err := parseDatabaseObjectID(d.Id(), "table")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	table, err := readTable(ctx, db, database, name, expandTable(d, d.Get))
+	if err != nil {
+		return diag.Errorf("failed reading table %s: %v", d.Id(), err)
+	}
+	if table == nil {
+		log.Printf("[WARN] Table (%s) not found; removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	flattenTable(d, table)
+
+	return nil
+}
+
+func DeleteTable(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	stmtSQL := fmt.Sprintf("DROP TABLE %s.%s", quoteIdentifier(d.Get("database").(string)), quoteIdentifier(d.Get("name").(string)))
+	logSQL(ctx, stmtSQL)
+
+	_, err = db.ExecContext(ctx, stmtSQL)
+	if err != nil {
+		return diag.Errorf("failed dropping table: %v", err)
+	}
+
+	return nil
+}
+
+func ImportTable(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	database, name, err := parseDatabaseObjectID(d.Id(), "table")
+	if err != nil {
+		return nil, err
+	}
+	d.Set("database", database)
+	d.Set("name", name)
+
+	diags := ReadTable(ctx, d, meta)
+	if diags.HasError() {
+		return nil, fmt.Errorf("failed reading table: %v", diags)
+	}
+	if d.Id() == "" {
+		return nil, fmt.Errorf("table %s.%s not found", database, name)
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func customizeDiffTable(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("column") || !d.NewValueKnown("primary_key") {
+		return nil
+	}
+
+	columns := map[string]bool{}
+	for _, raw := range d.Get("column").([]interface{}) {
+		column := raw.(map[string]interface{})
+		name := column["name"].(string)
+		if _, ok := columns[name]; ok {
+			return fmt.Errorf("column %s is defined more than once", name)
+		}
+		columns[name] = column["nullable"].(bool)
+	}
+
+	for _, raw := range d.Get("primary_key").([]interface{}) {
+		name := raw.(string)
+		nullable, ok := columns[name]
+		if !ok {
+			return fmt.Errorf("primary key column %s is not defined", name)
+		}
+		if nullable {
+			return fmt.Errorf("primary key column %s must set nullable = false", name)
+		}
+	}
+
+	if d.Id() != "" && !d.Get("allow_dropping_data").(bool) {
+		oldColumns, newColumns := d.GetChange("column")
+		if dropped := droppedColumns(expandTableColumns(oldColumns), expandTableColumns(newColumns)); len(dropped) > 0 {
+			return fmt.Errorf("dropping columns %s deletes their data: set previous_name on renamed columns, or set allow_dropping_data", strings.Join(dropped, ", "))
+		}
+	}
+
+	return nil
+}
+
+// checkTableSupport rejects features the server doesn't support before they
+// are silently ignored or fail with a syntax error.
+func checkTableSupport(db *StatementExecutor, table *tableDefinition) error {
+	if len(table.Checks) > 0 && !db.Server.Capabilities.CheckConstraints {
+		return fmt.Errorf("check constraints are not supported by %s %s", db.Server.Flavor, db.Server.VersionString)
+	}
+	for _, index := range table.Indexes {
+		if index.Invisible && !db.Server.Capabilities.InvisibleIndexes {
+			return fmt.Errorf("invisible indexes are not supported by %s %s", db.Server.Flavor, db.Server.VersionString)
+		}
+	}
+	for _, column := range table.Columns {
+		if column.GeneratedExpression != "" && !db.Server.Capabilities.GeneratedColumns {
+			return fmt.Errorf("generated columns are not supported by %s %s", db.Server.Flavor, db.Server.VersionString)
+		}
+	}
+	return nil
+}
+
+// expandTable reads the table from the resource data. get returns either
+// the old or the new values.
+func expandTable(d *schema.ResourceData, get func(string) interface{}) *tableDefinition {
+	table := &tableDefinition{
+		Database:      d.Get("database").(string),
+		Name:          d.Get("name").(string),
+		PrimaryKey:    expandStringList(get("primary_key").([]interface{})),
+		Engine:        get("engine").(string),
+		RowFormat:     get("row_format").(string),
+		CharacterSet:  get("default_character_set").(string),
+		Collation:     get("default_collation").(string),
+		Comment:       get("comment").(string),
+		AutoIncrement: get("auto_increment").(int),
+	}
+
+	table.Columns = expandTableColumns(get("column"))
+
+	for _, raw := range get("index").(*schema.Set).List() {
+		m := raw.(map[string]interface{})
+		table.Indexes = append(table.Indexes, tableIndex{
+			Name:      m["name"].(string),
+			Type:      strings.ToUpper(m["type"].(string)),
+			Columns:   expandStringList(m["columns"].([]interface{})),
+			Invisible: m["invisible"].(bool),
+		})
+	}
+	sort.Slice(table.Indexes, func(i, j int) bool { return table.Indexes[i].Name < table.Indexes[j].Name })
+
+	for _, raw := range get("foreign_key").(*schema.Set).List() {
+		m := raw.(map[string]interface{})
+		table.ForeignKeys = append(table.ForeignKeys, tableForeignKey{
+			Name:               m["name"].(string),
+			Columns:            expandStringList(m["columns"].([]interface{})),
+			ReferencedDatabase: m["referenced_database"].(string),
+			ReferencedTable:    m["referenced_table"].(string),
+			ReferencedColumns:  expandStringList(m["referenced_columns"].([]interface{})),
+			OnDelete:           strings.ToUpper(m["on_delete"].(string)),
+			OnUpdate:           strings.ToUpper(m["on_update"].(string)),
+		})
+	}
+	sort.Slice(table.ForeignKeys, func(i, j int) bool { return table.ForeignKeys[i].Name < table.ForeignKeys[j].Name })
+
+	for _, raw := range get("check").(*schema.Set).List() {
+		m := raw.(map[string]interface{})
+		table.Checks = append(table.Checks, tableCheck{
+			Name:       m["name"].(string),
+			Expression: m["expression"].(string),
+		})
+	}
+	sort.Slice(table.Checks, func(i, j int) bool { return table.Checks[i].Name < table.Checks[j].Name })
+
+	return table
+}
+
+func expandTableColumns(raw interface{}) []tableColumn {
+	var columns []tableColumn
+	for _, raw := range raw.([]interface{}) {
+		m := raw.(map[string]interface{})
+		columns = append(columns, tableColumn{
+			Name:                m["name"].(string),
+			PreviousName:        m["previous_name"].(string),
+			Type:                m["type"].(string),
+			Nullable:            m["nullable"].(bool),
+			Default:             m["default"].(string),
+			DefaultExpression:   m["default_expression"].(string),
+			AutoIncrement:       m["auto_increment"].(bool),
+			CharacterSet:        m["character_set"].(string),
+			Collation:           m["collation"].(string),
+			Comment:             m["comment"].(string),
+			GeneratedExpression: m["generated_expression"].(string),
+			GeneratedStored:     m["generated_stored"].(bool),
+		})
+	}
+	return columns
+}
+
+// markConfiguredDefaults sets DefaultSet on the columns with a default in
+// the configuration, as the resource data has no way to tell default = ""
+// from no default.
+func markConfiguredDefaults(columns []tableColumn, rawConfig cty.Value) {
+	if !isAttributeSet(rawConfig, "column") || !rawConfig.GetAttr("column").IsWhollyKnown() {
+		return
+	}
+	for i, column := range rawConfig.GetAttr("column").AsValueSlice() {
+		if i < len(columns) && isAttributeSet(column, "default") {
+			columns[i].DefaultSet = true
+		}
+	}
+}
+
+func expandStringList(raw []interface{}) []string {
+	list := make([]string, 0, len(raw))
+	for _, v := range raw {
+		s, _ := v.(string)
+		list = append(list, s)
+	}
+	return list
+}
+
+func flattenTable(d *schema.ResourceData, table *tableDefinition) {
+	columns := make([]interface{}, 0, len(table.Columns))
+	for _, c := range table.Columns {
+		columns = append(columns, map[string]interface{}{
+			"name":                 c.Name,
+			"previous_name":        c.PreviousName,
+			"type":                 c.Type,
+			"nullable":             c.Nullable,
+			"default":              c.Default,
+			"default_expression":   c.DefaultExpression,
+			"auto_increment":       c.AutoIncrement,
+			"character_set":        c.CharacterSet,
+			"collation":            c.Collation,
+			"comment":              c.Comment,
+			"generated_expression": c.GeneratedExpression,
+			"generated_stored":     c.GeneratedStored,
+		})
+	}
+
+	indexes := make([]interface{}, 0, len(table.Indexes))
+	for _, index := range table.Indexes {
+		indexes = append(indexes, map[string]interface{}{
+			"name":      index.Name,
+			"type":      index.Type,
+			"columns":   index.Columns,
+			"invisible": index.Invisible,
+		})
+	}
+
+	foreignKeys := make([]interface{}, 0, len(table.ForeignKeys))
+	for _, fk := range table.ForeignKeys {
+		foreignKeys = append(foreignKeys, map[string]interface{}{
+			"name":                fk.Name,
+			"columns":             fk.Columns,
+			"referenced_database": fk.ReferencedDatabase,
+			"referenced_table":    fk.ReferencedTable,
+			"referenced_columns":  fk.ReferencedColumns,
+			"on_delete":           fk.OnDelete,
+			"on_update":           fk.OnUpdate,
+		})
+	}
+
+	checks := make([]interface{}, 0, len(table.Checks))
+	for _, check := range table.Checks {
+		checks = append(checks, map[string]interface{}{
+			"name":       check.Name,
+			"expression": check.Expression,
+		})
+	}
+
+	d.Set("database", table.Database)
+	d.Set("name", table.Name)
+	d.Set("column", columns)
+	d.Set("primary_key", table.PrimaryKey)
+	d.Set("index", indexes)
+	d.Set("foreign_key", foreignKeys)
+	d.Set("check", checks)
+	d.Set("engine", table.Engine)
+	d.Set("row_format", table.RowFormat)
+	d.Set("default_character_set", table.CharacterSet)
+	d.Set("default_collation", table.Collation)
+	d.Set("comment", table.Comment)
+}
+
+// currentTimestampRegex matches the expressions MySQL 5.7 reports as the
+// default of TIMESTAMP and DATETIME columns without marking them as such.
+var currentTimestampRegex = regexp.MustCompile(`(?i)^current_timestamp(\(\d*\))?$`)
+
+func (c tableColumn) definition() string {
+	def := fmt.Sprintf("%s %s", quoteIdentifier(c.Name), c.Type)
+	if c.CharacterSet != "" && isStringColumnType(c.Type) {
+		def += " CHARACTER SET " + c.CharacterSet
+	}
+	if c.Collation != "" && isStringColumnType(c.Type) {
+		def += " COLLATE " + c.Collation
+	}
+	if c.GeneratedExpression != "" {
+		def += fmt.Sprintf(" GENERATED ALWAYS AS (%s)", c.GeneratedExpression)
+		if c.GeneratedStored {
+			def += " STORED"
+		} else {
+			def += " VIRTUAL"
+		}
+	}
+	if c.Nullable {
+		def += " NULL"
+	} else {
+		def += " NOT NULL"
+	}
+	switch {
+	case c.GeneratedExpression != "":
+	case c.DefaultExpression != "" && currentTimestampRegex.MatchString(c.DefaultExpression):
+		def += " DEFAULT " + c.DefaultExpression
+	case c.DefaultExpression != "":
+		def += fmt.Sprintf(" DEFAULT (%s)", c.DefaultExpression)
+	case c.Default != "" || c.DefaultSet:
+		def += " DEFAULT " + quoteString(c.Default)
+	}
+	if c.AutoIncrement {
+		def += " AUTO_INCREMENT"
+	}
+	if c.Comment != "" {
+		def += " COMMENT " + quoteString(c.Comment)
+	}
+	return def
+}
+
+// renamedColumns maps the old name of each column renamed through
+// previous_name to its new name.
+func renamedColumns(old, columns []tableColumn) map[string]string {
+	oldColumns := map[string]bool{}
+	for _, column := range old {
+		oldColumns[column.Name] = true
+	}
+	renames := map[string]string{}
+	for _, column := range columns {
+		if column.PreviousName != "" && !oldColumns[column.Name] && oldColumns[column.PreviousName] {
+			renames[column.PreviousName] = column.Name
+		}
+	}
+	return renames
+}
+
+func renameColumnList(columns []string, renames map[string]string) []string {
+	renamed := make([]string, len(columns))
+	for i, column := range columns {
+		name, length := column, ""
+		if m := indexColumnRegex.FindStringSubmatch(column); m != nil {
+			name, length = m[1], "("+m[2]+")"
+		}
+		if newName, ok := renames[name]; ok {
+			name = newName
+		}
+		renamed[i] = name + length
+	}
+	return renamed
+}
+
+// droppedColumns returns the columns of old which are neither kept nor
+// renamed in columns.
+func droppedColumns(old, columns []tableColumn) []string {
+	kept := renamedColumns(old, columns)
+	for _, column := range columns {
+		kept[column.Name] = column.Name
+	}
+	var dropped []string
+	for _, column := range old {
+		if _, ok := kept[column.Name]; !ok {
+			dropped = append(dropped, column.Name)
+		}
+	}
+	return dropped
+}
+
+func (c tableColumn) equal(other tableColumn) bool {
+	return c.Name == other.Name &&
+		normalizeColumnType(c.Type) == normalizeColumnType(other.Type) &&
+		c.Nullable == other.Nullable &&
+		c.Default == other.Default &&
+		normalizeExpression(c.DefaultExpression) == normalizeExpression(other.DefaultExpression) &&
+		c.AutoIncrement == other.AutoIncrement &&
+		c.CharacterSet == other.CharacterSet &&
+		c.Collation == other.Collation &&
+		c.Comment == other.Comment &&
+		normalizeExpression(c.GeneratedExpression) == normalizeExpression(other.GeneratedExpression) &&
+		c.GeneratedStored == other.GeneratedStored
+}
+
+func (index tableIndex) definition() string {
+	columns := make([]string, len(index.Columns))
+	for i, column := range index.Columns {
+		columns[i] = quoteIndexColumn(column)
+	}
+
+	def := "INDEX"
+	if index.Type != "INDEX" {
+		def = index.Type + " INDEX"
+	}
+	def = fmt.Sprintf("%s %s (%s)", def, quoteIdentifier(index.Name), strings.Join(columns, ", "))
+	if index.Invisible {
+		def += " INVISIBLE"
+	}
+	return def
+}
+
+// equal tells whether the indexes are the same, apart from their visibility.
+func (index tableIndex) equal(other tableIndex) bool {
+	return index.Name == other.Name && index.Type == other.Type && strings.Join(index.Columns, ",") == strings.Join(other.Columns, ",")
+}
+
+// indexColumnRegex matches index columns with a prefix length, like name(10).
+var indexColumnRegex = regexp.MustCompile(`^(.+)\((\d+)\)$`)
+
+func quoteIndexColumn(column string) string {
+	if m := indexColumnRegex.FindStringSubmatch(column); m != nil {
+		return fmt.Sprintf("%s(%s)", quoteIdentifier(m[1]), m[2])
+	}
+	return quoteIdentifier(column)
+}
+
+func (fk tableForeignKey) definition(database string) string {
+	referencedDatabase := fk.ReferencedDatabase
+	if referencedDatabase == "" {
+		referencedDatabase = database
+	}
+	def := fmt.Sprintf("CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s.%s (%s)",
+		quoteIdentifier(fk.Name), quoteIdentifierList(fk.Columns),
+		quoteIdentifier(referencedDatabase), quoteIdentifier(fk.ReferencedTable), quoteIdentifierList(fk.ReferencedColumns))
+	if fk.OnDelete != "" {
+		def += " ON DELETE " + fk.OnDelete
+	}
+	if fk.OnUpdate != "" {
+		def += " ON UPDATE " + fk.OnUpdate
+	}
+	return def
+}
+
+func (fk tableForeignKey) equal(other tableForeignKey, database string) bool {
+	referencedDatabase := func(fk tableForeignKey) string {
+		if fk.ReferencedDatabase == "" {
+			return database
+		}
+		return fk.ReferencedDatabase
+	}
+	return fk.Name == other.Name &&
+		strings.Join(fk.Columns, ",") == strings.Join(other.Columns, ",") &&
+		referencedDatabase(fk) == referencedDatabase(other) &&
+		fk.ReferencedTable == other.ReferencedTable &&
+		strings.Join(fk.ReferencedColumns, ",") == strings.Join(other.ReferencedColumns, ",") &&
+		normalizeReferentialAction(fk.OnDelete) == normalizeReferentialAction(other.OnDelete) &&
+		normalizeReferentialAction(fk.OnUpdate) == normalizeReferentialAction(other.OnUpdate)
+}
+
+func (check tableCheck) definition() string {
+	return fmt.Sprintf("CONSTRAINT %s CHECK (%s)", quoteIdentifier(check.Name), check.Expression)
+}
+
+func quoteIdentifierList(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = quoteIdentifier(name)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func createTableSQL(table *tableDefinition) string {
+	var defs []string
+	for _, column := range table.Columns {
+		defs = append(defs, column.definition())
+	}
+	if len(table.PrimaryKey) > 0 {
+		defs = append(defs, fmt.Sprintf("PRIMARY KEY (%s)", quoteIdentifierList(table.PrimaryKey)))
+	}
+	for _, index := range table.Indexes {
+		defs = append(defs, index.definition())
+	}
+	for _, fk := range table.ForeignKeys {
+		defs = append(defs, fk.definition(table.Database))
+	}
+	for _, check := range table.Checks {
+		defs = append(defs, check.definition())
+	}
+
+	stmtSQL := fmt.Sprintf("CREATE TABLE %s (\n  %s\n)", table.identifier(), strings.Join(defs, ",\n  "))
+	if options := tableOptions(nil, table); len(options) > 0 {
+		stmtSQL += " " + strings.Join(options, " ")
+	}
+	return stmtSQL
+}
+
+// tableOptions returns the options of table that differ from old, all of
+// them if old is nil.
+func tableOptions(old, table *tableDefinition) []string {
+	if old == nil {
+		old = &tableDefinition{}
+	}
+
+	var options []string
+	if table.Engine != "" && !strings.EqualFold(table.Engine, old.Engine) {
+		options = append(options, "ENGINE = "+table.Engine)
+	}
+	if table.RowFormat != "" && !strings.EqualFold(table.RowFormat, old.RowFormat) {
+		options = append(options, "ROW_FORMAT = "+table.RowFormat)
+	}
+	if table.CharacterSet != "" && table.CharacterSet != old.CharacterSet {
+		options = append(options, "DEFAULT CHARACTER SET = "+table.CharacterSet)
+	}
+	if table.Collation != "" && table.Collation != old.Collation {
+		options = append(options, "DEFAULT COLLATE = "+table.Collation)
+	}
+	if table.Comment != old.Comment {
+		options = append(options, "COMMENT = "+quoteString(table.Comment))
+	}
+	if table.AutoIncrement > 0 && table.AutoIncrement != old.AutoIncrement {
+		options = append(options, fmt.Sprintf("AUTO_INCREMENT = %d", table.AutoIncrement))
+	}
+	return options
+}
+
+// alterTableSQL returns the statements changing old into table, with as few
+// changes as possible. Foreign keys are dropped in a statement of their own,
+// as one can't be dropped and added again in the same statement.
+func alterTableSQL(old, table *tableDefinition, flavor ServerFlavor) []string {
+	var drops, clauses []string
+
+	// The server renames columns in keys itself, so keys are compared
+	// using the new column names.
+	renames := renamedColumns(old.Columns, table.Columns)
+
+	newForeignKeys := map[string]tableForeignKey{}
+	for _, fk := range table.ForeignKeys {
+		newForeignKeys[fk.Name] = fk
+	}
+	oldForeignKeys := map[string]tableForeignKey{}
+	for _, fk := range old.ForeignKeys {
+		fk.Columns = renameColumnList(fk.Columns, renames)
+		oldForeignKeys[fk.Name] = fk
+		if newFk, ok := newForeignKeys[fk.Name]; !ok || !newFk.equal(fk, table.Database) {
+			drops = append(drops, "DROP FOREIGN KEY "+quoteIdentifier(fk.Name))
+		}
+	}
+
+	newChecks := map[string]tableCheck{}
+	for _, check := range table.Checks {
+		newChecks[check.Name] = check
+	}
+	oldChecks := map[string]tableCheck{}
+	for _, check := range old.Checks {
+		oldChecks[check.Name] = check
+		if newCheck, ok := newChecks[check.Name]; !ok || normalizeExpression(newCheck.Expression) != normalizeExpression(check.Expression) {
+			if flavor == FlavorMariaDB {
+				clauses = append(clauses, "DROP CONSTRAINT "+quoteIdentifier(check.Name))
+			} else {
+				clauses = append(clauses, "DROP CHECK "+quoteIdentifier(check.Name))
+			}
+		}
+	}
+
+	newIndexes := map[string]tableIndex{}
+	for _, index := range table.Indexes {
+		newIndexes[index.Name] = index
+	}
+	oldIndexes := map[string]tableIndex{}
+	for _, index := range old.Indexes {
+		index.Columns = renameColumnList(index.Columns, renames)
+		oldIndexes[index.Name] = index
+		if newIndex, ok := newIndexes[index.Name]; !ok || !newIndex.equal(index) {
+			clauses = append(clauses, "DROP INDEX "+quoteIdentifier(index.Name))
+		}
+	}
+
+	primaryKeyChanged := strings.Join(renameColumnList(old.PrimaryKey, renames), ",") != strings.Join(table.PrimaryKey, ",")
+	if primaryKeyChanged && len(old.PrimaryKey) > 0 {
+		clauses = append(clauses, "DROP PRIMARY KEY")
+	}
+
+	newColumns := map[string]bool{}
+	for _, column := range table.Columns {
+		newColumns[column.Name] = true
+	}
+	// oldColumns is keyed by the new name of the columns kept.
+	oldColumns := map[string]tableColumn{}
+	var oldOrder []string
+	for _, column := range old.Columns {
+		name := column.Name
+		if newName, ok := renames[name]; ok {
+			name = newName
+		} else if !newColumns[name] {
+			clauses = append(clauses, "DROP COLUMN "+quoteIdentifier(name))
+			continue
+		}
+		oldColumns[name] = column
+		oldOrder = append(oldOrder, name)
+	}
+
+	// Columns are only moved if the order of the ones kept changed. Then
+	// they are all moved, in their new order.
+	var newOrder []string
+	for _, column := range table.Columns {
+		if _, ok := oldColumns[column.Name]; ok {
+			newOrder = append(newOrder, column.Name)
+		}
+	}
+	reorder := strings.Join(oldOrder, ",") != strings.Join(newOrder, ",")
+
+	for i, column := range table.Columns {
+		position := " FIRST"
+		if i > 0 {
+			position = " AFTER " + quoteIdentifier(table.Columns[i-1].Name)
+		}
+
+		oldColumn, ok := oldColumns[column.Name]
+		switch {
+		case ok && oldColumn.Name != column.Name && reorder:
+			clauses = append(clauses, "CHANGE COLUMN "+quoteIdentifier(oldColumn.Name)+" "+column.definition()+position)
+		case ok && oldColumn.Name != column.Name:
+			clauses = append(clauses, "CHANGE COLUMN "+quoteIdentifier(oldColumn.Name)+" "+column.definition())
+		case !ok && i == len(table.Columns)-1:
+			clauses = append(clauses, "ADD COLUMN "+column.definition())
+		case !ok:
+			clauses = append(clauses, "ADD COLUMN "+column.definition()+position)
+		case reorder:
+			clauses = append(clauses, "MODIFY COLUMN "+column.definition()+position)
+		case !oldColumn.equal(column):
+			clauses = append(clauses, "MODIFY COLUMN "+column.definition())
+		}
+	}
+
+	if primaryKeyChanged && len(table.PrimaryKey) > 0 {
+		clauses = append(clauses, fmt.Sprintf("ADD PRIMARY KEY (%s)", quoteIdentifierList(table.PrimaryKey)))
+	}
+
+	for _, index := range table.Indexes {
+		oldIndex, ok := oldIndexes[index.Name]
+		switch {
+		case !ok || !oldIndex.equal(index):
+			clauses = append(clauses, "ADD "+index.definition())
+		case oldIndex.Invisible && !index.Invisible:
+			clauses = append(clauses, fmt.Sprintf("ALTER INDEX %s VISIBLE", quoteIdentifier(index.Name)))
+		case !oldIndex.Invisible && index.Invisible:
+			clauses = append(clauses, fmt.Sprintf("ALTER INDEX %s INVISIBLE", quoteIdentifier(index.Name)))
+		}
+	}
+
+	for _, check := range table.Checks {
+		if oldCheck, ok := oldChecks[check.Name]; !ok || normalizeExpression(oldCheck.Expression) != normalizeExpression(check.Expression) {
+			clauses = append(clauses, "ADD "+check.definition())
+		}
+	}
+
+	for _, fk := range table.ForeignKeys {
+		if oldFk, ok := oldForeignKeys[fk.Name]; !ok || !oldFk.equal(fk, table.Database) {
+			clauses = append(clauses, "ADD "+fk.definition(table.Database))
+		}
+	}
+
+	clauses = append(clauses, tableOptions(old, table)...)
+
+	var stmts []string
+	if len(drops) > 0 {
+		stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s %s", table.identifier(), strings.Join(drops, ", ")))
+	}
+	if len(clauses) > 0 {
+		stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s %s", table.identifier(), strings.Join(clauses, ", ")))
+	}
+	return stmts
+}
+
+// readTable reads the table from information_schema, or returns nil if it
+// doesn't exist. Values the server implies, like the character set of
+// columns using the default of the table, are only set if prior set them.
+func readTable(ctx context.Context, db *StatementExecutor, database, name string, prior *tableDefinition) (*tableDefinition, error) {
+	table := &tableDefinition{
+		Database: database,
+		Name:     name,
+		// The server moves it on every insert.
+		AutoIncrement: prior.AutoIncrement,
+	}
+
+	stmtSQL := `SELECT t.ENGINE, t.ROW_FORMAT, t.TABLE_COLLATION, c.CHARACTER_SET_NAME, t.TABLE_COMMENT
+FROM information_schema.TABLES t
+LEFT JOIN information_schema.COLLATIONS c ON c.COLLATION_NAME = t.TABLE_COLLATION
+WHERE t.TABLE_SCHEMA = ? AND t.TABLE_NAME = ? AND t.TABLE_TYPE = 'BASE TABLE'`
+	logSQL(ctx, stmtSQL)
+
+	var engine, rowFormat, collation, characterSet sql.NullString
+	err := db.QueryRowContext(ctx, stmtSQL, database, name).Scan(&engine, &rowFormat, &collation, &characterSet, &table.Comment)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	table.Engine = engine.String
+	table.RowFormat = strings.ToUpper(rowFormat.String)
+	table.Collation = collation.String
+	table.CharacterSet = characterSet.String
+
+	if err := readTableColumns(ctx, db, table, prior); err != nil {
+		return nil, err
+	}
+	if err := readTableIndexes(ctx, db, table, prior); err != nil {
+		return nil, err
+	}
+	if err := readTableForeignKeys(ctx, db, table, prior); err != nil {
+		return nil, err
+	}
+	if db.Server.Capabilities.CheckConstraints {
+		if err := readTableChecks(ctx, db, table, db.Server.Flavor); err != nil {
+			return nil, err
+		}
+	}
+
+	return table, nil
+}
+
+func readTableColumns(ctx context.Context, db *StatementExecutor, table *tableDefinition, prior *tableDefinition) error {
+	generationExpression := "''"
+	if db.Server.Capabilities.GeneratedColumns {
+		generationExpression = "c.GENERATION_EXPRESSION"
+	}
+	stmtSQL := fmt.Sprintf(`SELECT c.COLUMN_NAME, c.COLUMN_TYPE, c.IS_NULLABLE, c.COLUMN_DEFAULT, c.EXTRA,
+	c.CHARACTER_SET_NAME, c.COLLATION_NAME, co.IS_DEFAULT, c.COLUMN_COMMENT, %s
+FROM information_schema.COLUMNS c
+LEFT JOIN information_schema.COLLATIONS co ON co.COLLATION_NAME = c.COLLATION_NAME
+WHERE c.TABLE_SCHEMA = ? AND c.TABLE_NAME = ?
+ORDER BY c.ORDINAL_POSITION`, generationExpression)
+	logSQL(ctx, stmtSQL)
+
+	rows, err := db.QueryContext(ctx, stmtSQL, table.Database, table.Name)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	priorColumns := map[string]tableColumn{}
+	for _, column := range prior.Columns {
+		priorColumns[column.Name] = column
+	}
+
+	for rows.Next() {
+		var column tableColumn
+		var nullable, extra string
+		var columnDefault, characterSet, collation, defaultCollation, generationExpression sql.NullString
+		err := rows.Scan(&column.Name, &column.Type, &nullable, &columnDefault, &extra,
+			&characterSet, &collation, &defaultCollation, &column.Comment, &generationExpression)
+		if err != nil {
+			return err
+		}
+
+		column.Nullable = nullable == "YES"
+		column.AutoIncrement = strings.Contains(strings.ToLower(extra), "auto_increment")
+		column.GeneratedExpression = generationExpression.String
+		column.GeneratedStored = strings.Contains(strings.ToUpper(extra), "STORED GENERATED")
+		if column.GeneratedExpression == "" && columnDefault.Valid {
+			column.Default, column.DefaultExpression = parseColumnDefault(column.Type, columnDefault.String, extra, db.Server.Flavor)
+		}
+
+		priorColumn := priorColumns[column.Name]
+		column.PreviousName = priorColumn.PreviousName
+		column.CharacterSet = impliedValue(characterSet.String, characterSet.String == table.CharacterSet, priorColumn.CharacterSet)
+		column.Collation = impliedValue(collation.String, collation.String == table.Collation || defaultCollation.String == "Yes", priorColumn.Collation)
+
+		table.Columns = append(table.Columns, column)
+	}
+	return rows.Err()
+}
+
+// numberRegex matches numeric literals, which MariaDB doesn't quote in
+// COLUMN_DEFAULT.
+var numberRegex = regexp.MustCompile(`^-?\d+(\.\d+)?([eE][-+]?\d+)?$`)
+
+// parseColumnDefault tells literal defaults from expressions. MySQL marks
+// expressions with DEFAULT_GENERATED in EXTRA, MariaDB quotes literals
+// instead.
+func parseColumnDefault(columnType, value, extra string, flavor ServerFlavor) (string, string) {
+	if flavor == FlavorMariaDB {
+		switch {
+		case value == "NULL":
+			return "", ""
+		case len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+			return strings.ReplaceAll(value[1:len(value)-1], "''", "'"), ""
+		case numberRegex.MatchString(value):
+			return value, ""
+		default:
+			return "", value
+		}
+	}
+
+	if strings.Contains(strings.ToUpper(extra), "DEFAULT_GENERATED") {
+		return "", value
+	}
+	columnType = strings.ToLower(columnType)
+	if (strings.HasPrefix(columnType, "timestamp") || strings.HasPrefix(columnType, "datetime")) && currentTimestampRegex.MatchString(value) {
+		return "", value
+	}
+	return value, ""
+}
+
+func readTableIndexes(ctx context.Context, db *StatementExecutor, table *tableDefinition, prior *tableDefinition) error {
+	visible := "'YES'"
+	if db.Server.Capabilities.InvisibleIndexes {
+		visible = "IS_VISIBLE"
+	}
+	stmtSQL := fmt.Sprintf(`SELECT INDEX_NAME, NON_UNIQUE, INDEX_TYPE, COLUMN_NAME, SUB_PART, %s
+FROM information_schema.STATISTICS
+WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?
+ORDER BY INDEX_NAME, SEQ_IN_INDEX`, visible)
+	logSQL(ctx, stmtSQL)
+
+	rows, err := db.QueryContext(ctx, stmtSQL, table.Database, table.Name)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var indexes []*tableIndex
+	for rows.Next() {
+		var name, indexType, isVisible string
+		var nonUnique int
+		var column sql.NullString
+		var subPart sql.NullInt64
+		if err := rows.Scan(&name, &nonUnique, &indexType, &column, &subPart, &isVisible); err != nil {
+			return err
+		}
+
+		columnName := column.String
+		if subPart.Valid {
+			columnName = fmt.Sprintf("%s(%d)", columnName, subPart.Int64)
+		}
+		if name == "PRIMARY" {
+			table.PrimaryKey = append(table.PrimaryKey, columnName)
+			continue
+		}
+
+		if len(indexes) == 0 || indexes[len(indexes)-1].Name != name {
+			index := &tableIndex{Name: name, Type: "INDEX", Invisible: isVisible == "NO"}
+			switch {
+			case indexType == "FULLTEXT" || indexType == "SPATIAL":
+				index.Type = indexType
+			case nonUnique == 0:
+				index.Type = "UNIQUE"
+			}
+			indexes = append(indexes, index)
+		}
+		index := indexes[len(indexes)-1]
+		index.Columns = append(index.Columns, columnName)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	priorIndexes := map[string]bool{}
+	for _, index := range prior.Indexes {
+		priorIndexes[index.Name] = true
+	}
+	foreignKeys := map[string]bool{}
+	for _, fk := range prior.ForeignKeys {
+		foreignKeys[fk.Name] = true
+	}
+	for _, index := range indexes {
+		// The server adds indexes for foreign keys that need one.
+		if foreignKeys[index.Name] && !priorIndexes[index.Name] {
+			continue
+		}
+		table.Indexes = append(table.Indexes, *index)
+	}
+	return nil
+}
+
+func readTableForeignKeys(ctx context.Context, db *StatementExecutor, table *tableDefinition, prior *tableDefinition) error {
+	stmtSQL := `SELECT k.CONSTRAINT_NAME, k.COLUMN_NAME, k.REFERENCED_TABLE_SCHEMA, k.REFERENCED_TABLE_NAME, k.REFERENCED_COLUMN_NAME,
+	r.DELETE_RULE, r.UPDATE_RULE
+FROM information_schema.KEY_COLUMN_USAGE k
+JOIN information_schema.REFERENTIAL_CONSTRAINTS r ON r.CONSTRAINT_SCHEMA = k.CONSTRAINT_SCHEMA
+	AND r.CONSTRAINT_NAME = k.CONSTRAINT_NAME AND r.TABLE_NAME = k.TABLE_NAME
+WHERE k.TABLE_SCHEMA = ? AND k.TABLE_NAME = ? AND k.REFERENCED_TABLE_NAME IS NOT NULL
+ORDER BY k.CONSTRAINT_NAME, k.ORDINAL_POSITION`
+	logSQL(ctx, stmtSQL)
+
+	rows, err := db.QueryContext(ctx, stmtSQL, table.Database, table.Name)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	priorForeignKeys := map[string]tableForeignKey{}
+	for _, fk := range prior.ForeignKeys {
+		priorForeignKeys[fk.Name] = fk
+	}
+
+	for rows.Next() {
+		var name, column, referencedDatabase, referencedTable, referencedColumn, onDelete, onUpdate string
+		err := rows.Scan(&name, &column, &referencedDatabase, &referencedTable, &referencedColumn, &onDelete, &onUpdate)
+		if err != nil {
+			return err
+		}
+
+		n := len(table.ForeignKeys)
+		if n == 0 || table.ForeignKeys[n-1].Name != name {
+			priorFk := priorForeignKeys[name]
+			table.ForeignKeys = append(table.ForeignKeys, tableForeignKey{
+				Name:               name,
+				ReferencedDatabase: impliedValue(referencedDatabase, referencedDatabase == table.Database, priorFk.ReferencedDatabase),
+				ReferencedTable:    referencedTable,
+				OnDelete:           onDelete,
+				OnUpdate:           onUpdate,
+			})
+			n++
+		}
+		fk := &table.ForeignKeys[n-1]
+		fk.Columns = append(fk.Columns, column)
+		fk.ReferencedColumns = append(fk.ReferencedColumns, referencedColumn)
+	}
+	return rows.Err()
+}
+
+func readTableChecks(ctx context.Context, db *StatementExecutor, table *tableDefinition, flavor ServerFlavor) error {
+	stmtSQL := `SELECT cc.CONSTRAINT_NAME, cc.CHECK_CLAUSE
+FROM information_schema.TABLE_CONSTRAINTS tc
+JOIN information_schema.CHECK_CONSTRAINTS cc ON cc.CONSTRAINT_SCHEMA = tc.CONSTRAINT_SCHEMA
+	AND cc.CONSTRAINT_NAME = tc.CONSTRAINT_NAME
+WHERE tc.TABLE_SCHEMA = ? AND tc.TABLE_NAME = ? AND tc.CONSTRAINT_TYPE = 'CHECK'
+ORDER BY cc.CONSTRAINT_NAME`
+	if flavor == FlavorMariaDB {
+		stmtSQL = `SELECT CONSTRAINT_NAME, CHECK_CLAUSE
+FROM information_schema.CHECK_CONSTRAINTS
+WHERE CONSTRAINT_SCHEMA = ? AND TABLE_NAME = ?
+ORDER BY CONSTRAINT_NAME`
+	}
+	logSQL(ctx, stmtSQL)
+
+	rows, err := db.QueryContext(ctx, stmtSQL, table.Database, table.Name)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	columns := map[string]bool{}
+	for _, column := range table.Columns {
+		columns[column.Name] = true
+	}
+
+	for rows.Next() {
+		var check tableCheck
+		if err := rows.Scan(&check.Name, &check.Expression); err != nil {
+			return err
+		}
+		// MariaDB names the checks of columns, like the one of JSON
+		// columns, after them.
+		if flavor == FlavorMariaDB && columns[check.Name] {
+			continue
+		}
+		table.Checks = append(table.Checks, check)
+	}
+	return rows.Err()
+}
+
+// impliedValue returns value, unless the server implied it and it wasn't
+// set explicitly before, so configurations don't have to repeat defaults.
+func impliedValue(value string, implied bool, prior string) string {
+	if implied && prior != value {
+		return ""
+	}
+	return value
+}
+
+var stringColumnTypeRegex = regexp.MustCompile(`(?i)^\s*(char|varchar|tinytext|text|mediumtext|longtext|enum|set)\b`)
+
+func isStringColumnType(columnType string) bool {
+	return stringColumnTypeRegex.MatchString(columnType)
+}
+
+var (
+	integerDisplayWidthRegex = regexp.MustCompile(`\b(tinyint|smallint|mediumint|int|bigint)\(\d+\)`)
+)
+
+// normalizeColumnType makes equivalent column types comparable: integer
+// display widths are deprecated and only reported by some servers.
+func normalizeColumnType(columnType string) string {
+	columnType = joinColumnTypeTokens(sqlTokens(columnType))
+	columnType = integerDisplayWidthRegex.ReplaceAllString(columnType, "$1")
+	switch {
+	case strings.HasPrefix(columnType, "integer"):
+		columnType = "int" + strings.TrimPrefix(columnType, "integer")
+	case columnType == "bool" || columnType == "boolean":
+		columnType = "tinyint"
+	case columnType == "decimal" || columnType == "numeric":
+		columnType = "decimal(10,0)"
+	}
+	return columnType
+}
+
+// joinColumnTypeTokens joins the tokens of a column type the way the server
+// reports it, without spaces around parentheses and commas.
+func joinColumnTypeTokens(tokens []string) string {
+	var b strings.Builder
+	for i, token := range tokens {
+		if i > 0 && tokens[i-1] != "(" && tokens[i-1] != "," && token != "(" && token != ")" && token != "," {
+			b.WriteByte(' ')
+		}
+		b.WriteString(token)
+	}
+	return b.String()
+}
+
+// normalizeExpression makes expressions comparable with the way the server
+// reports them, with quoted identifiers, lower case functions, character set
+// introducers and extra parentheses. String literals are compared exactly.
+func normalizeExpression(expression string) string {
+	tokens := sqlTokens(expression)
+	for len(tokens) > 1 && tokens[0] == "(" && enclosedInParentheses(tokens) {
+		tokens = tokens[1 : len(tokens)-1]
+	}
+	return strings.Join(tokens, " ")
+}
+
+// enclosedInParentheses tells whether the first parenthesis of the tokens
+// closes at their end.
+func enclosedInParentheses(tokens []string) bool {
+	depth := 0
+	for i, token := range tokens {
+		switch token {
+		case "(":
+			depth++
+		case ")":
+			depth--
+			if depth == 0 && i != len(tokens)-1 {
+				return false
+			}
+		}
+	}
+	return depth == 0
+}
+
+// sqlTokens splits SQL into tokens that compare equal however they are
+// written. Keywords and identifiers are lower cased and unquoted, string
+// literals are quoted the same way and lose their character set introducer,
+// and whitespace is dropped. information_schema reports the literals of
+// check constraints quoted as \'...\', which is read as a quote too.
+func sqlTokens(s string) []string {
+	var tokens []string
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '\'' || c == '"':
+			value, end := readSQLString(s, i+1, s[i:i+1])
+			tokens = append(tokens, quoteString(value))
+			i = end
+		case c == '\\' && strings.HasPrefix(s[i:], `\'`):
+			value, end := readSQLString(s, i+2, `\'`)
+			tokens = append(tokens, quoteString(value))
+			i = end
+		case c == '`':
+			end := strings.IndexByte(s[i+1:], '`')
+			if end < 0 {
+				end = len(s) - i - 1
+			}
+			tokens = append(tokens, strings.ToLower(s[i+1:i+1+end]))
+			i += end + 2
+		case isSQLWordByte(c):
+			j := i
+			for j < len(s) && isSQLWordByte(s[j]) {
+				j++
+			}
+			word := strings.ToLower(s[i:j])
+			if !(word[0] == '_' && (strings.HasPrefix(s[j:], "'") || strings.HasPrefix(s[j:], `\'`))) {
+				tokens = append(tokens, word)
+			}
+			i = j
+		default:
+			tokens = append(tokens, string(c))
+			i++
+		}
+	}
+	return tokens
+}
+
+func isSQLWordByte(c byte) bool {
+	return c == '_' || c == '$' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// readSQLString reads the string literal starting at start up to the quote
+// closing it, and returns its value and the position after the quote.
+func readSQLString(s string, start int, quote string) (string, int) {
+	var value strings.Builder
+	for i := start; i < len(s); {
+		switch {
+		case strings.HasPrefix(s[i:], quote+quote) && quote != `\'`:
+			value.WriteString(quote)
+			i += 2
+		case strings.HasPrefix(s[i:], quote):
+			return value.String(), i + len(quote)
+		case s[i] == '\\' && i+1 < len(s):
+			value.WriteString(unescapeSQLChar(s[i+1]))
+			i += 2
+		default:
+			value.WriteByte(s[i])
+			i++
+		}
+	}
+	return value.String(), len(s)
+}
+
+func unescapeSQLChar(c byte) string {
+	switch c {
+	case '0':
+		return "\x00"
+	case 'n':
+		return "\n"
+	case 'r':
+		return "\r"
+	case 't':
+		return "\t"
+	}
+	return string(c)
+}
+
+func normalizeReferentialAction(action string) string {
+	action = strings.ToUpper(action)
+	// InnoDB treats both the same, and reports the default as either.
+	if action == "" || action == "NO ACTION" {
+		return "RESTRICT"
+	}
+	return action
+}
+
+func suppressColumnTypeDiff(k, old, new string, d *schema.ResourceData) bool {
+	return normalizeColumnType(old) == normalizeColumnType(new)
+}
+
+func suppressExpressionDiff(k, old, new string, d *schema.ResourceData) bool {
+	return normalizeExpression(old) == normalizeExpression(new)
+}
+
+func suppressCaseDiff(k, old, new string, d *schema.ResourceData) bool {
+	return strings.EqualFold(old, new)
+}
+
+func suppressReferentialActionDiff(k, old, new string, d *schema.ResourceData) bool {
+	return normalizeReferentialAction(old) == normalizeReferentialAction(new)
+}
+
+// The set hashes normalize values like their diffs are suppressed, so
+// equivalent elements read from the server don't show up as changes.
+
+func tableIndexHash(v interface{}) int {
+	m := v.(map[string]interface{})
+	return schema.HashString(fmt.Sprintf("%s|%s|%s|%v", hashString(m, "name"), strings.ToUpper(hashString(m, "type")),
+		hashStringList(m, "columns"), m["invisible"]))
+}
+
+func tableForeignKeyHash(v interface{}) int {
+	m := v.(map[string]interface{})
+	return schema.HashString(fmt.Sprintf("%s|%s|%s|%s|%s|%s|%s", hashString(m, "name"), hashStringList(m, "columns"),
+		hashString(m, "referenced_database"), hashString(m, "referenced_table"), hashStringList(m, "referenced_columns"),
+		normalizeReferentialAction(hashString(m, "on_delete")), normalizeReferentialAction(hashString(m, "on_update"))))
+}
+
+func tableCheckHash(v interface{}) int {
+	m := v.(map[string]interface{})
+	return schema.HashString(fmt.Sprintf("%s|%s", hashString(m, "name"), normalizeExpression(hashString(m, "expression"))))
+}
+
+func hashString(m map[string]interface{}, key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+func hashStringList(m map[string]interface{}, key string) string {
+	list, _ := m[key].([]interface{})
+	return strings.Join(expandStringList(list), ",")
+}
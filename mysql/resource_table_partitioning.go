@@ -0,0 +1,504 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var partitionTypes = []string{"RANGE", "RANGE COLUMNS", "LIST", "LIST COLUMNS", "HASH", "LINEAR HASH", "KEY", "LINEAR KEY"}
+
+func resourceTablePartitioning() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateTablePartitioning,
+		UpdateContext: UpdateTablePartitioning,
+		ReadContext:   ReadTablePartitioning,
+		DeleteContext: DeleteTablePartitioning,
+		Importer: &schema.ResourceImporter{
+			StateContext: ImportTablePartitioning,
+		},
+
+		CustomizeDiff: customizeDiffTablePartitioning,
+
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"table": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"type": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateFunc:     validation.StringInSlice(partitionTypes, true),
+				DiffSuppressFunc: suppressCaseDiff,
+			},
+
+			"expression": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "",
+				DiffSuppressFunc: suppressExpressionDiff,
+			},
+
+			"partition": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"values": {
+							Type:             schema.TypeString,
+							Required:         true,
+							DiffSuppressFunc: suppressExpressionDiff,
+						},
+					},
+				},
+			},
+
+			"partitions": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+
+			"allow_dropping_data": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+		},
+	}
+}
+
+type tablePartition struct {
+	Name   string
+	Values string
+}
+
+type tablePartitioning struct {
+	Database   string
+	Table      string
+	Type       string
+	Expression string
+	// Partitions are the partitions of RANGE and LIST partitioning, Count
+	// the number of HASH and KEY partitions.
+	Partitions []tablePartition
+	Count      int
+}
+
+// isHashPartitioning tells whether partitions of the type are only counted,
+// rather than listed with their values.
+func isHashPartitioning(partitionType string) bool {
+	partitionType = strings.ToUpper(partitionType)
+	return strings.HasSuffix(partitionType, "HASH") || strings.HasSuffix(partitionType, "KEY")
+}
+
+func customizeDiffTablePartitioning(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("type") || !d.NewValueKnown("partition") || !d.NewValueKnown("expression") {
+		return nil
+	}
+
+	partitionType := d.Get("type").(string)
+	partitions := d.Get("partition").([]interface{})
+	if isHashPartitioning(partitionType) {
+		if len(partitions) > 0 {
+			return fmt.Errorf("%s partitioning takes partitions instead of partition blocks", strings.ToUpper(partitionType))
+		}
+		if _, ok := d.GetOk("partitions"); !ok && d.NewValueKnown("partitions") {
+			return fmt.Errorf("%s partitioning requires partitions", strings.ToUpper(partitionType))
+		}
+		return nil
+	}
+
+	if len(partitions) == 0 {
+		return fmt.Errorf("%s partitioning requires partition blocks", strings.ToUpper(partitionType))
+	}
+	if d.Get("expression").(string) == "" {
+		return fmt.Errorf("%s partitioning requires an expression", strings.ToUpper(partitionType))
+	}
+	names := map[string]bool{}
+	for _, raw := range partitions {
+		name := raw.(map[string]interface{})["name"].(string)
+		if names[strings.ToLower(name)] {
+			return fmt.Errorf("partition %s is defined more than once", name)
+		}
+		names[strings.ToLower(name)] = true
+	}
+	// The number of partitions follows the blocks.
+	if d.HasChange("partition") {
+		return d.SetNew("partitions", len(partitions))
+	}
+	return nil
+}
+
+// CreateTablePartitioning partitions the existing table, which rebuilds it.
+func CreateTablePartitioning(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	partitioning := expandTablePartitioning(d, d.Get)
+	stmtSQL := fmt.Sprintf("ALTER TABLE %s.%s %s", quoteIdentifier(partitioning.Database), quoteIdentifier(partitioning.Table), partitionByClause(partitioning))
+	logSQL(ctx, stmtSQL)
+
+	_, err = db.ExecContext(ctx, stmtSQL)
+	if err != nil {
+		return diag.Errorf("failed partitioning table: %v", err)
+	}
+
+	d.SetId(databaseObjectID(partitioning.Database, partitioning.Table))
+
+	return ReadTablePartitioning(ctx, d, meta)
+}
+
+func UpdateTablePartitioning(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	oldPartitioning := expandTablePartitioning(d, func(key string) interface{} {
+		old, _ := d.GetChange(key)
+		return old
+	})
+	newPartitioning := expandTablePartitioning(d, d.Get)
+
+	if !d.Get("allow_dropping_data").(bool) {
+		dropped := droppedPartitions(oldPartitioning, newPartitioning)
+		if len(dropped) == len(oldPartitioning.Partitions) {
+			// The table is partitioned anew, keeping its rows.
+			dropped = nil
+		}
+		for _, name := range dropped {
+			hasRows, err := partitionHasRows(ctx, db, newPartitioning, name)
+			if err != nil {
+				return diag.Errorf("failed checking partition %s for rows: %v", name, err)
+			}
+			if hasRows {
+				return diag.Errorf("partition %s of %s holds rows, set allow_dropping_data to drop it", name, d.Id())
+			}
+		}
+	}
+
+	for _, stmtSQL := range alterPartitioningSQL(oldPartitioning, newPartitioning) {
+		logSQL(ctx, stmtSQL)
+
+		_, err = db.ExecContext(ctx, stmtSQL)
+		if err != nil {
+			// Earlier statements may have been applied, so the state is
+			// read back from the server rather than taken from the config.
+			diags := diag.Errorf("failed changing partitions: %v", err)
+			return append(diags, ReadTablePartitioning(ctx, d, meta)...)
+		}
+	}
+
+	return ReadTablePartitioning(ctx, d, meta)
+}
+
+func ReadTablePartitioning(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	database, table, err := parseDatabaseObjectID(d.Id(), "table")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	partitioning, err := readTablePartitioning(ctx, db, database, table)
+	if err != nil {
+		return diag.Errorf("failed reading partitions of %s: %v", d.Id(), err)
+	}
+	if partitioning == nil {
+		log.Printf("[WARN] Partitioning of table (%s) not found; removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("database", partitioning.Database)
+	d.Set("table", partitioning.Table)
+	d.Set("type", partitioning.Type)
+	d.Set("expression", partitioning.Expression)
+	partitions := make([]interface{}, 0, len(partitioning.Partitions))
+	for _, partition := range partitioning.Partitions {
+		partitions = append(partitions, map[string]interface{}{
+			"name":   partition.Name,
+			"values": partition.Values,
+		})
+	}
+	d.Set("partition", partitions)
+	d.Set("partitions", partitioning.Count)
+
+	return nil
+}
+
+// DeleteTablePartitioning removes the partitioning, keeping the rows of all
+// partitions in the table.
+func DeleteTablePartitioning(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	stmtSQL := fmt.Sprintf("ALTER TABLE %s.%s REMOVE PARTITIONING", quoteIdentifier(d.Get("database").(string)), quoteIdentifier(d.Get("table").(string)))
+	logSQL(ctx, stmtSQL)
+
+	_, err = db.ExecContext(ctx, stmtSQL)
+	if err != nil {
+		return diag.Errorf("failed removing partitioning: %v", err)
+	}
+
+	return nil
+}
+
+func ImportTablePartitioning(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	database, table, err := parseDatabaseObjectID(d.Id(), "table")
+	if err != nil {
+		return nil, err
+	}
+	d.Set("database", database)
+	d.Set("table", table)
+	d.Set("allow_dropping_data", false)
+
+	diags := ReadTablePartitioning(ctx, d, meta)
+	if diags.HasError() {
+		return nil, fmt.Errorf("failed reading partitions: %v", diags)
+	}
+	if d.Id() == "" {
+		return nil, fmt.Errorf("table %s.%s is not partitioned", database, table)
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// expandTablePartitioning reads the partitioning from the resource data. get
+// returns either the old or the new values.
+func expandTablePartitioning(d *schema.ResourceData, get func(string) interface{}) *tablePartitioning {
+	partitioning := &tablePartitioning{
+		Database:   get("database").(string),
+		Table:      get("table").(string),
+		Type:       strings.ToUpper(get("type").(string)),
+		Expression: get("expression").(string),
+		Count:      get("partitions").(int),
+	}
+	for _, raw := range get("partition").([]interface{}) {
+		partition := raw.(map[string]interface{})
+		partitioning.Partitions = append(partitioning.Partitions, tablePartition{
+			Name:   partition["name"].(string),
+			Values: partition["values"].(string),
+		})
+	}
+	return partitioning
+}
+
+func partitionByClause(partitioning *tablePartitioning) string {
+	clause := fmt.Sprintf("PARTITION BY %s (%s)", partitioning.Type, partitioning.Expression)
+	if isHashPartitioning(partitioning.Type) {
+		return fmt.Sprintf("%s PARTITIONS %d", clause, partitioning.Count)
+	}
+	return fmt.Sprintf("%s (%s)", clause, partitionDefinitionsSQL(partitioning.Type, partitioning.Partitions))
+}
+
+func partitionDefinitionsSQL(partitionType string, partitions []tablePartition) string {
+	definitions := make([]string, len(partitions))
+	for i, partition := range partitions {
+		values := strings.TrimSpace(partition.Values)
+		switch {
+		case strings.HasPrefix(partitionType, "LIST"):
+			definitions[i] = fmt.Sprintf("PARTITION %s VALUES IN (%s)", quoteIdentifier(partition.Name), values)
+		case strings.EqualFold(values, "MAXVALUE"):
+			definitions[i] = fmt.Sprintf("PARTITION %s VALUES LESS THAN MAXVALUE", quoteIdentifier(partition.Name))
+		default:
+			definitions[i] = fmt.Sprintf("PARTITION %s VALUES LESS THAN (%s)", quoteIdentifier(partition.Name), values)
+		}
+	}
+	return strings.Join(definitions, ", ")
+}
+
+// samePartition compares the values of partitions as expressions, so only
+// string literals are compared exactly, as they set where rows go.
+func samePartition(a, b tablePartition) bool {
+	return strings.EqualFold(a.Name, b.Name) && normalizeExpression(a.Values) == normalizeExpression(b.Values)
+}
+
+// droppedPartitions returns the partitions of old that new doesn't have.
+// alterPartitioningSQL deletes their rows, unless they are all dropped.
+func droppedPartitions(old, new *tablePartitioning) []string {
+	if !samePartitionScheme(old, new) || isHashPartitioning(new.Type) {
+		return nil
+	}
+
+	names := map[string]bool{}
+	for _, partition := range new.Partitions {
+		names[strings.ToLower(partition.Name)] = true
+	}
+	var dropped []string
+	for _, partition := range old.Partitions {
+		if !names[strings.ToLower(partition.Name)] {
+			dropped = append(dropped, partition.Name)
+		}
+	}
+	return dropped
+}
+
+func samePartitionScheme(old, new *tablePartitioning) bool {
+	return strings.EqualFold(old.Type, new.Type) && normalizeExpression(old.Expression) == normalizeExpression(new.Expression)
+}
+
+// alterPartitioningSQL returns the statements changing the partitions from
+// old to new without rebuilding the table. Removed partitions are dropped.
+// Partitions added at the end are added, and the fewest partitions in
+// between are reorganized for other changes. Changing the type or expression
+// partitions the table anew.
+func alterPartitioningSQL(old, new *tablePartitioning) []string {
+	table := fmt.Sprintf("%s.%s", quoteIdentifier(new.Database), quoteIdentifier(new.Table))
+
+	if !samePartitionScheme(old, new) {
+		return []string{fmt.Sprintf("ALTER TABLE %s %s", table, partitionByClause(new))}
+	}
+
+	if isHashPartitioning(new.Type) {
+		switch {
+		case new.Count > old.Count:
+			return []string{fmt.Sprintf("ALTER TABLE %s ADD PARTITION PARTITIONS %d", table, new.Count-old.Count)}
+		case new.Count < old.Count:
+			return []string{fmt.Sprintf("ALTER TABLE %s COALESCE PARTITION %d", table, old.Count-new.Count)}
+		}
+		return nil
+	}
+
+	dropped := droppedPartitions(old, new)
+	if len(dropped) == len(old.Partitions) {
+		// Dropping every partition isn't possible, so the table is
+		// partitioned anew.
+		return []string{fmt.Sprintf("ALTER TABLE %s %s", table, partitionByClause(new))}
+	}
+
+	var stmts []string
+	if len(dropped) > 0 {
+		names := make([]string, len(dropped))
+		for i, name := range dropped {
+			names[i] = quoteIdentifier(name)
+		}
+		stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s DROP PARTITION %s", table, strings.Join(names, ", ")))
+	}
+
+	isDropped := map[string]bool{}
+	for _, name := range dropped {
+		isDropped[name] = true
+	}
+	var kept []tablePartition
+	for _, partition := range old.Partitions {
+		if !isDropped[partition.Name] {
+			kept = append(kept, partition)
+		}
+	}
+
+	partitions := new.Partitions
+	prefix := 0
+	for prefix < len(kept) && prefix < len(partitions) && samePartition(kept[prefix], partitions[prefix]) {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(kept)-prefix && suffix < len(partitions)-prefix && samePartition(kept[len(kept)-1-suffix], partitions[len(partitions)-1-suffix]) {
+		suffix++
+	}
+	oldMiddle := kept[prefix : len(kept)-suffix]
+	newMiddle := partitions[prefix : len(partitions)-suffix]
+
+	switch {
+	case len(newMiddle) == 0 && len(oldMiddle) == 0:
+	case len(oldMiddle) == 0 && suffix == 0:
+		stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s ADD PARTITION (%s)", table, partitionDefinitionsSQL(new.Type, newMiddle)))
+	default:
+		if len(oldMiddle) == 0 {
+			// Partitions inserted before others split the one after them.
+			oldMiddle = kept[prefix : prefix+1]
+			newMiddle = partitions[prefix : len(partitions)-suffix+1]
+		}
+		names := make([]string, len(oldMiddle))
+		for i, partition := range oldMiddle {
+			names[i] = quoteIdentifier(partition.Name)
+		}
+		stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s REORGANIZE PARTITION %s INTO (%s)", table, strings.Join(names, ", "), partitionDefinitionsSQL(new.Type, newMiddle)))
+	}
+
+	return stmts
+}
+
+// partitionHasRows tells whether the partition of the table holds any row.
+func partitionHasRows(ctx context.Context, db *StatementExecutor, partitioning *tablePartitioning, name string) (bool, error) {
+	stmtSQL := fmt.Sprintf("SELECT 1 FROM %s.%s PARTITION (%s) LIMIT 1", quoteIdentifier(partitioning.Database), quoteIdentifier(partitioning.Table), quoteIdentifier(name))
+	logSQL(ctx, stmtSQL)
+
+	var found int
+	err := db.QueryRowContext(ctx, stmtSQL).Scan(&found)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// readTablePartitioning reads the partitions from information_schema, or
+// returns nil if the table isn't partitioned. Subpartitions aren't supported,
+// so tables using them are an error rather than read without them.
+func readTablePartitioning(ctx context.Context, db *StatementExecutor, database, table string) (*tablePartitioning, error) {
+	partitioning := &tablePartitioning{Database: database, Table: table}
+
+	stmtSQL := `SELECT PARTITION_NAME, PARTITION_METHOD, PARTITION_EXPRESSION, PARTITION_DESCRIPTION, SUBPARTITION_METHOD
+FROM information_schema.PARTITIONS
+WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND PARTITION_NAME IS NOT NULL
+	AND (SUBPARTITION_ORDINAL_POSITION IS NULL OR SUBPARTITION_ORDINAL_POSITION = 1)
+ORDER BY PARTITION_ORDINAL_POSITION`
+	logSQL(ctx, stmtSQL)
+
+	rows, err := db.QueryContext(ctx, stmtSQL, database, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		var expression, description, subpartitionMethod sql.NullString
+		if err := rows.Scan(&name, &partitioning.Type, &expression, &description, &subpartitionMethod); err != nil {
+			return nil, err
+		}
+		if subpartitionMethod.String != "" {
+			return nil, fmt.Errorf("table %s.%s uses %s subpartitioning, which isn't supported", database, table, subpartitionMethod.String)
+		}
+		partitioning.Expression = expression.String
+		partitioning.Count++
+		if !isHashPartitioning(partitioning.Type) {
+			partitioning.Partitions = append(partitioning.Partitions, tablePartition{Name: name, Values: description.String})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if partitioning.Count == 0 {
+		return nil, nil
+	}
+	return partitioning, nil
+}
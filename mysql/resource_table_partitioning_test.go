@@ -0,0 +1,296 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccTablePartitioning_basic(t *testing.T) {
+	dbName := "tf_table_partitioning_test"
+	resourceName := "mysql_table_partitioning.orders"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipTiDB(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccTablePartitioningCheckDestroy(dbName, "orders"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTablePartitioningConfig(dbName, `
+  partition {
+    name   = "p10"
+    values = "10"
+  }
+
+  partition {
+    name   = "p20"
+    values = "20"
+  }
+`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccTablePartitioningExists(dbName, "orders", 2),
+					resource.TestCheckResourceAttr(resourceName, "id", dbName+".orders"),
+					resource.TestCheckResourceAttr(resourceName, "type", "RANGE"),
+					resource.TestCheckResourceAttr(resourceName, "partitions", "2"),
+				),
+			},
+			{
+				Config: testAccTablePartitioningConfig(dbName, `
+  partition {
+    name   = "p5"
+    values = "5"
+  }
+
+  partition {
+    name   = "p10"
+    values = "10"
+  }
+
+  partition {
+    name   = "p20"
+    values = "20"
+  }
+
+  partition {
+    name   = "future"
+    values = "MAXVALUE"
+  }
+`),
+				Check: resource.ComposeTestCheckFunc(
+					testAccTablePartitioningExists(dbName, "orders", 4),
+					resource.TestCheckResourceAttr(resourceName, "partition.0.name", "p5"),
+					resource.TestCheckResourceAttr(resourceName, "partition.3.values", "MAXVALUE"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateId:           dbName + ".orders",
+				ImportStateVerifyIgnore: []string{"allow_dropping_data"},
+			},
+		},
+	})
+}
+
+func TestPartitionByClause(t *testing.T) {
+	testCases := map[string]struct {
+		partitioning *tablePartitioning
+		expected     string
+	}{
+		"range": {
+			&tablePartitioning{Type: "RANGE", Expression: "YEAR(created_at)", Partitions: []tablePartition{{"p2025", "2026"}, {"future", "maxvalue"}}},
+			"PARTITION BY RANGE (YEAR(created_at)) (PARTITION `p2025` VALUES LESS THAN (2026), PARTITION `future` VALUES LESS THAN MAXVALUE)",
+		},
+		"list": {
+			&tablePartitioning{Type: "LIST COLUMNS", Expression: "region", Partitions: []tablePartition{{"eu", "'de','fr'"}}},
+			"PARTITION BY LIST COLUMNS (region) (PARTITION `eu` VALUES IN ('de','fr'))",
+		},
+		"hash": {
+			&tablePartitioning{Type: "LINEAR HASH", Expression: "user_id", Count: 8},
+			"PARTITION BY LINEAR HASH (user_id) PARTITIONS 8",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if clause := partitionByClause(tc.partitioning); clause != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, clause)
+			}
+		})
+	}
+}
+
+func TestSamePartition(t *testing.T) {
+	equivalent := map[string][2]string{
+		"spacing":    {"'de', 'fr'", "'de','fr'"},
+		"introducer": {"'de'", "_utf8mb4'de'"},
+		"maxvalue":   {"maxvalue", "MAXVALUE"},
+	}
+	for name, tc := range equivalent {
+		t.Run(name, func(t *testing.T) {
+			if !samePartition(tablePartition{"p", tc[0]}, tablePartition{"p", tc[1]}) {
+				t.Errorf("expected %q and %q to be the same", tc[0], tc[1])
+			}
+		})
+	}
+
+	different := map[string][2]string{
+		"case":    {"'EU'", "'eu'"},
+		"spacing": {"'2024-01-01 00:00:00'", "'2024-01-0100:00:00'"},
+	}
+	for name, tc := range different {
+		t.Run(name, func(t *testing.T) {
+			if samePartition(tablePartition{"p", tc[0]}, tablePartition{"p", tc[1]}) {
+				t.Errorf("expected %q and %q to differ", tc[0], tc[1])
+			}
+		})
+	}
+}
+
+func TestAlterPartitioningSQL(t *testing.T) {
+	rangePartitioning := func(partitions ...tablePartition) *tablePartitioning {
+		return &tablePartitioning{Database: "shop", Table: "orders", Type: "RANGE", Expression: "id", Partitions: partitions, Count: len(partitions)}
+	}
+	hashPartitioning := func(count int) *tablePartitioning {
+		return &tablePartitioning{Database: "shop", Table: "orders", Type: "HASH", Expression: "id", Count: count}
+	}
+
+	testCases := map[string]struct {
+		old, new *tablePartitioning
+		expected []string
+		dropped  []string
+	}{
+		"unchanged": {
+			rangePartitioning(tablePartition{"p10", "10"}),
+			rangePartitioning(tablePartition{"p10", "(10)"}),
+			nil,
+			nil,
+		},
+		"add at the end": {
+			rangePartitioning(tablePartition{"p10", "10"}),
+			rangePartitioning(tablePartition{"p10", "10"}, tablePartition{"p20", "20"}, tablePartition{"future", "MAXVALUE"}),
+			[]string{"ALTER TABLE `shop`.`orders` ADD PARTITION (PARTITION `p20` VALUES LESS THAN (20), PARTITION `future` VALUES LESS THAN MAXVALUE)"},
+			nil,
+		},
+		"split": {
+			rangePartitioning(tablePartition{"p10", "10"}, tablePartition{"future", "MAXVALUE"}),
+			rangePartitioning(tablePartition{"p10", "10"}, tablePartition{"p20", "20"}, tablePartition{"future", "MAXVALUE"}),
+			[]string{"ALTER TABLE `shop`.`orders` REORGANIZE PARTITION `future` INTO (PARTITION `p20` VALUES LESS THAN (20), PARTITION `future` VALUES LESS THAN MAXVALUE)"},
+			nil,
+		},
+		"drop and change bound": {
+			rangePartitioning(tablePartition{"p10", "10"}, tablePartition{"p20", "20"}, tablePartition{"p30", "30"}),
+			rangePartitioning(tablePartition{"p20", "20"}, tablePartition{"p30", "40"}),
+			[]string{
+				"ALTER TABLE `shop`.`orders` DROP PARTITION `p10`",
+				"ALTER TABLE `shop`.`orders` REORGANIZE PARTITION `p30` INTO (PARTITION `p30` VALUES LESS THAN (40))",
+			},
+			[]string{"p10"},
+		},
+		"merge": {
+			rangePartitioning(tablePartition{"p10", "10"}, tablePartition{"p20", "20"}, tablePartition{"p30", "30"}),
+			rangePartitioning(tablePartition{"p10", "10"}, tablePartition{"p30", "30"}),
+			[]string{"ALTER TABLE `shop`.`orders` DROP PARTITION `p20`"},
+			[]string{"p20"},
+		},
+		"rename": {
+			rangePartitioning(tablePartition{"p10", "10"}, tablePartition{"p20", "20"}, tablePartition{"p30", "30"}),
+			rangePartitioning(tablePartition{"p10", "10"}, tablePartition{"p15", "15"}, tablePartition{"p20", "20"}, tablePartition{"p30", "30"}),
+			[]string{"ALTER TABLE `shop`.`orders` REORGANIZE PARTITION `p20` INTO (PARTITION `p15` VALUES LESS THAN (15), PARTITION `p20` VALUES LESS THAN (20))"},
+			nil,
+		},
+		"replace all": {
+			rangePartitioning(tablePartition{"p10", "10"}),
+			rangePartitioning(tablePartition{"future", "MAXVALUE"}),
+			[]string{"ALTER TABLE `shop`.`orders` PARTITION BY RANGE (id) (PARTITION `future` VALUES LESS THAN MAXVALUE)"},
+			[]string{"p10"},
+		},
+		"change type": {
+			rangePartitioning(tablePartition{"p10", "10"}),
+			hashPartitioning(4),
+			[]string{"ALTER TABLE `shop`.`orders` PARTITION BY HASH (id) PARTITIONS 4"},
+			nil,
+		},
+		"add hash partitions": {
+			hashPartitioning(4),
+			hashPartitioning(6),
+			[]string{"ALTER TABLE `shop`.`orders` ADD PARTITION PARTITIONS 2"},
+			nil,
+		},
+		"coalesce hash partitions": {
+			hashPartitioning(6),
+			hashPartitioning(4),
+			[]string{"ALTER TABLE `shop`.`orders` COALESCE PARTITION 2"},
+			nil,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			stmts := alterPartitioningSQL(tc.old, tc.new)
+			if !reflect.DeepEqual(stmts, tc.expected) {
+				t.Errorf("expected\n%s\ngot\n%s", strings.Join(tc.expected, "\n"), strings.Join(stmts, "\n"))
+			}
+			if dropped := droppedPartitions(tc.old, tc.new); !reflect.DeepEqual(dropped, tc.dropped) {
+				t.Errorf("expected dropped partitions %v, got %v", tc.dropped, dropped)
+			}
+		})
+	}
+}
+
+func testAccTablePartitioningExists(dbName, table string, partitions int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		ctx := context.Background()
+		db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+		if err != nil {
+			return err
+		}
+
+		var count int
+		err = db.QueryRow("SELECT COUNT(*) FROM information_schema.PARTITIONS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND PARTITION_NAME IS NOT NULL", dbName, table).Scan(&count)
+		if err != nil {
+			return err
+		}
+		if count != partitions {
+			return fmt.Errorf("expected %d partitions of %s.%s, found %d", partitions, dbName, table, count)
+		}
+		return nil
+	}
+}
+
+func testAccTablePartitioningCheckDestroy(dbName, table string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		ctx := context.Background()
+		db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+		if err != nil {
+			return err
+		}
+
+		var count int
+		err = db.QueryRow("SELECT COUNT(*) FROM information_schema.PARTITIONS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND PARTITION_NAME IS NOT NULL", dbName, table).Scan(&count)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return fmt.Errorf("table %s.%s is still partitioned", dbName, table)
+		}
+		return nil
+	}
+}
+
+func testAccTablePartitioningConfig(dbName, partitions string) string {
+	return fmt.Sprintf(`
+resource "mysql_database" "test" {
+  name = "%s"
+}
+
+resource "mysql_table" "orders" {
+  database = mysql_database.test.name
+  name     = "orders"
+
+  column {
+    name     = "id"
+    type     = "int"
+    nullable = false
+  }
+
+  primary_key = ["id"]
+}
+
+resource "mysql_table_partitioning" "orders" {
+  database   = mysql_database.test.name
+  table      = mysql_table.orders.name
+  type       = "RANGE"
+  expression = "id"
+%s}
+`, dbName, partitions)
+}
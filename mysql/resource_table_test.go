@@ -0,0 +1,525 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccTable_basic(t *testing.T) {
+	dbName := "tf_table_test"
+	resourceName := "mysql_table.orders"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccTableCheckDestroy(dbName, "orders"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTableConfigBasic(dbName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccTableExists(dbName, "orders"),
+					resource.TestCheckResourceAttr(resourceName, "id", dbName+".orders"),
+					resource.TestCheckResourceAttr(resourceName, "column.#", "3"),
+					resource.TestCheckResourceAttr(resourceName, "column.0.auto_increment", "true"),
+					resource.TestCheckResourceAttr(resourceName, "column.2.default", "new"),
+					resource.TestCheckResourceAttr(resourceName, "primary_key.0", "id"),
+					resource.TestCheckResourceAttr(resourceName, "index.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "foreign_key.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "engine", "InnoDB"),
+					resource.TestCheckResourceAttr(resourceName, "comment", "Orders"),
+				),
+			},
+			{
+				Config: testAccTableConfigUpdated(dbName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "column.#", "4"),
+					resource.TestCheckResourceAttr(resourceName, "column.2.name", "note"),
+					resource.TestCheckResourceAttr(resourceName, "column.3.type", "varchar(32)"),
+					resource.TestCheckResourceAttr(resourceName, "index.#", "2"),
+					resource.TestCheckResourceAttr(resourceName, "comment", "All orders"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateId:           dbName + ".orders",
+				ImportStateVerifyIgnore: []string{"auto_increment"},
+			},
+		},
+	})
+}
+
+func TestAlterTableSQL(t *testing.T) {
+	base := func() *tableDefinition {
+		return &tableDefinition{
+			Database: "shop",
+			Name:     "orders",
+			Columns: []tableColumn{
+				{Name: "id", Type: "int", AutoIncrement: true},
+				{Name: "customer_id", Type: "int(11)", Nullable: true},
+				{Name: "status", Type: "varchar(16)", Default: "new"},
+			},
+			PrimaryKey: []string{"id"},
+			Indexes:    []tableIndex{{Name: "status_idx", Type: "INDEX", Columns: []string{"status"}}},
+			ForeignKeys: []tableForeignKey{{
+				Name: "customer_fk", Columns: []string{"customer_id"},
+				ReferencedTable: "customers", ReferencedColumns: []string{"id"}, OnDelete: "NO ACTION",
+			}},
+			Engine: "InnoDB",
+		}
+	}
+
+	testCases := map[string]struct {
+		change   func(table *tableDefinition)
+		expected []string
+	}{
+		"unchanged": {
+			change: func(table *tableDefinition) {
+				// Equivalent to what the server reports.
+				table.Columns[1].Type = "INT"
+				table.ForeignKeys[0].OnDelete = ""
+				table.ForeignKeys[0].ReferencedDatabase = "shop"
+				table.Engine = "innodb"
+			},
+		},
+		"add column": {
+			change: func(table *tableDefinition) {
+				table.Columns = append(table.Columns, tableColumn{Name: "note", Type: "text", Nullable: true, Comment: "Free text"})
+			},
+			expected: []string{"ALTER TABLE `shop`.`orders` ADD COLUMN `note` text NULL COMMENT 'Free text'"},
+		},
+		"insert column": {
+			change: func(table *tableDefinition) {
+				table.Columns = append(table.Columns[:1], append([]tableColumn{{Name: "created_at", Type: "timestamp", DefaultExpression: "CURRENT_TIMESTAMP"}}, table.Columns[1:]...)...)
+			},
+			expected: []string{"ALTER TABLE `shop`.`orders` ADD COLUMN `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP AFTER `id`"},
+		},
+		"modify and drop column": {
+			change: func(table *tableDefinition) {
+				table.Columns[2].Type = "varchar(32)"
+				table.Columns[2].CharacterSet = "latin1"
+				table.Columns = append(table.Columns[:1], table.Columns[2])
+				table.ForeignKeys = nil
+			},
+			expected: []string{
+				"ALTER TABLE `shop`.`orders` DROP FOREIGN KEY `customer_fk`",
+				"ALTER TABLE `shop`.`orders` DROP COLUMN `customer_id`, MODIFY COLUMN `status` varchar(32) CHARACTER SET latin1 NOT NULL DEFAULT 'new'",
+			},
+		},
+		"rename column": {
+			change: func(table *tableDefinition) {
+				table.Columns[2].Name = "state"
+				table.Columns[2].PreviousName = "status"
+				table.Indexes[0].Columns = []string{"state"}
+			},
+			expected: []string{"ALTER TABLE `shop`.`orders` CHANGE COLUMN `status` `state` varchar(16) NOT NULL DEFAULT 'new'"},
+		},
+		"reorder columns": {
+			change: func(table *tableDefinition) {
+				table.Columns[1], table.Columns[2] = table.Columns[2], table.Columns[1]
+			},
+			expected: []string{"ALTER TABLE `shop`.`orders` MODIFY COLUMN `id` int NOT NULL AUTO_INCREMENT FIRST, MODIFY COLUMN `status` varchar(16) NOT NULL DEFAULT 'new' AFTER `id`, MODIFY COLUMN `customer_id` int(11) NULL AFTER `status`"},
+		},
+		"change foreign key": {
+			change: func(table *tableDefinition) {
+				table.ForeignKeys[0].OnDelete = "CASCADE"
+			},
+			expected: []string{
+				"ALTER TABLE `shop`.`orders` DROP FOREIGN KEY `customer_fk`",
+				"ALTER TABLE `shop`.`orders` ADD CONSTRAINT `customer_fk` FOREIGN KEY (`customer_id`) REFERENCES `shop`.`customers` (`id`) ON DELETE CASCADE",
+			},
+		},
+		"indexes": {
+			change: func(table *tableDefinition) {
+				table.Indexes[0].Invisible = true
+				table.Indexes = append(table.Indexes, tableIndex{Name: "note_idx", Type: "FULLTEXT", Columns: []string{"status(8)"}})
+			},
+			expected: []string{"ALTER TABLE `shop`.`orders` ALTER INDEX `status_idx` INVISIBLE, ADD FULLTEXT INDEX `note_idx` (`status`(8))"},
+		},
+		"primary key and checks": {
+			change: func(table *tableDefinition) {
+				table.PrimaryKey = []string{"id", "status"}
+				table.Checks = []tableCheck{{Name: "status_check", Expression: "status <> ''"}}
+			},
+			expected: []string{"ALTER TABLE `shop`.`orders` DROP PRIMARY KEY, ADD PRIMARY KEY (`id`, `status`), ADD CONSTRAINT `status_check` CHECK (status <> '')"},
+		},
+		"options": {
+			change: func(table *tableDefinition) {
+				table.Comment = "Orders"
+				table.RowFormat = "COMPRESSED"
+				table.AutoIncrement = 1000
+			},
+			expected: []string{"ALTER TABLE `shop`.`orders` ROW_FORMAT = COMPRESSED, COMMENT = 'Orders', AUTO_INCREMENT = 1000"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			table := base()
+			tc.change(table)
+			stmts := alterTableSQL(base(), table, FlavorMySQL)
+			if !reflect.DeepEqual(stmts, tc.expected) {
+				t.Errorf("expected:\n%q\ngot:\n%q", tc.expected, stmts)
+			}
+		})
+	}
+}
+
+func TestDroppedColumns(t *testing.T) {
+	old := []tableColumn{{Name: "id"}, {Name: "status"}, {Name: "note"}}
+	columns := []tableColumn{{Name: "id"}, {Name: "state", PreviousName: "status"}}
+	if dropped := droppedColumns(old, columns); !reflect.DeepEqual(dropped, []string{"note"}) {
+		t.Errorf("expected only note to be dropped, got %q", dropped)
+	}
+}
+
+func TestAlterTableSQL_DropCheck(t *testing.T) {
+	old := &tableDefinition{Database: "shop", Name: "orders", Checks: []tableCheck{{Name: "status_check", Expression: "(`status` <> _utf8mb4\\'\\')"}}}
+	table := &tableDefinition{Database: "shop", Name: "orders", Checks: []tableCheck{{Name: "status_check", Expression: "status <> ''"}}}
+	if stmts := alterTableSQL(old, table, FlavorMySQL); len(stmts) != 0 {
+		t.Errorf("expected equivalent expressions not to change, got %q", stmts)
+	}
+
+	table.Checks = nil
+	if stmts := alterTableSQL(old, table, FlavorMySQL); !reflect.DeepEqual(stmts, []string{"ALTER TABLE `shop`.`orders` DROP CHECK `status_check`"}) {
+		t.Errorf("unexpected statements for MySQL: %q", stmts)
+	}
+	if stmts := alterTableSQL(old, table, FlavorMariaDB); !reflect.DeepEqual(stmts, []string{"ALTER TABLE `shop`.`orders` DROP CONSTRAINT `status_check`"}) {
+		t.Errorf("unexpected statements for MariaDB: %q", stmts)
+	}
+}
+
+func TestCreateTableSQL(t *testing.T) {
+	table := &tableDefinition{
+		Database: "shop",
+		Name:     "orders",
+		Columns: []tableColumn{
+			{Name: "id", Type: "bigint unsigned", AutoIncrement: true},
+			{Name: "total", Type: "decimal(10,2)", Default: "0"},
+			{Name: "total_cents", Type: "bigint", Nullable: true, GeneratedExpression: "total * 100", GeneratedStored: true},
+			{Name: "code", Type: "char(8)", CharacterSet: "ascii", Collation: "ascii_bin", DefaultExpression: "uuid()"},
+			{Name: "note", Type: "varchar(64)", DefaultSet: true},
+		},
+		PrimaryKey:    []string{"id"},
+		Indexes:       []tableIndex{{Name: "code_idx", Type: "UNIQUE", Columns: []string{"code"}}},
+		Checks:        []tableCheck{{Name: "total_check", Expression: "total >= 0"}},
+		Engine:        "InnoDB",
+		CharacterSet:  "utf8mb4",
+		Comment:       "Orders",
+		AutoIncrement: 100,
+	}
+
+	expected := "CREATE TABLE `shop`.`orders` (\n" +
+		"  `id` bigint unsigned NOT NULL AUTO_INCREMENT,\n" +
+		"  `total` decimal(10,2) NOT NULL DEFAULT '0',\n" +
+		"  `total_cents` bigint GENERATED ALWAYS AS (total * 100) STORED NULL,\n" +
+		"  `code` char(8) CHARACTER SET ascii COLLATE ascii_bin NOT NULL DEFAULT (uuid()),\n" +
+		"  `note` varchar(64) NOT NULL DEFAULT '',\n" +
+		"  PRIMARY KEY (`id`),\n" +
+		"  UNIQUE INDEX `code_idx` (`code`),\n" +
+		"  CONSTRAINT `total_check` CHECK (total >= 0)\n" +
+		") ENGINE = InnoDB DEFAULT CHARACTER SET = utf8mb4 COMMENT = 'Orders' AUTO_INCREMENT = 100"
+	if stmtSQL := createTableSQL(table); stmtSQL != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, stmtSQL)
+	}
+}
+
+func TestMarkConfiguredDefaults(t *testing.T) {
+	columnType := cty.Object(map[string]cty.Type{"name": cty.String, "default": cty.String})
+	config := cty.ObjectVal(map[string]cty.Value{
+		"column": cty.ListVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal("id"), "default": cty.NullVal(cty.String)}),
+			cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal("note"), "default": cty.StringVal("")}),
+		}),
+	})
+	columns := []tableColumn{{Name: "id"}, {Name: "note"}}
+	markConfiguredDefaults(columns, config)
+	if columns[0].DefaultSet || !columns[1].DefaultSet {
+		t.Errorf("expected only the configured default to be set, got %+v", columns)
+	}
+
+	// Without a configuration, the columns are left as they are.
+	markConfiguredDefaults(columns, cty.NullVal(cty.Object(map[string]cty.Type{"column": cty.List(columnType)})))
+}
+
+func TestParseColumnDefault(t *testing.T) {
+	testCases := map[string]struct {
+		columnType string
+		value      string
+		extra      string
+		flavor     ServerFlavor
+		literal    string
+		expression string
+	}{
+		"mysql literal":           {"varchar(16)", "new", "", FlavorMySQL, "new", ""},
+		"mysql expression":        {"char(36)", "uuid()", "DEFAULT_GENERATED", FlavorMySQL, "", "uuid()"},
+		"mysql 5.7 timestamp":     {"timestamp", "CURRENT_TIMESTAMP", "on update CURRENT_TIMESTAMP", FlavorMySQL, "", "CURRENT_TIMESTAMP"},
+		"mysql timestamp literal": {"varchar(32)", "CURRENT_TIMESTAMP", "", FlavorMySQL, "CURRENT_TIMESTAMP", ""},
+		"mariadb literal":         {"varchar(16)", "'it''s'", "", FlavorMariaDB, "it's", ""},
+		"mariadb number":          {"int(11)", "-1.5", "", FlavorMariaDB, "-1.5", ""},
+		"mariadb null":            {"int(11)", "NULL", "", FlavorMariaDB, "", ""},
+		"mariadb expression":      {"timestamp", "current_timestamp()", "", FlavorMariaDB, "", "current_timestamp()"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			literal, expression := parseColumnDefault(tc.columnType, tc.value, tc.extra, tc.flavor)
+			if literal != tc.literal || expression != tc.expression {
+				t.Errorf("expected %q and %q, got %q and %q", tc.literal, tc.expression, literal, expression)
+			}
+		})
+	}
+}
+
+func TestNormalizeColumnType(t *testing.T) {
+	testCases := map[string]string{
+		"INT(11)":               "int",
+		"int(10) unsigned":      "int unsigned",
+		"Integer":               "int",
+		"bool":                  "tinyint",
+		"tinyint(1)":            "tinyint",
+		"DECIMAL":               "decimal(10,0)",
+		"varchar(255)":          "varchar(255)",
+		"enum('a',  'b')":       "enum('a','b')",
+		"ENUM('Small','LARGE')": "enum('Small','LARGE')",
+		"set('it''s', \"x\")":   "set('it\\'s','x')",
+		"DECIMAL(10, 2)":        "decimal(10,2)",
+		"bigint(20) zerofill":   "bigint zerofill",
+	}
+
+	for columnType, expected := range testCases {
+		if normalized := normalizeColumnType(columnType); normalized != expected {
+			t.Errorf("expected %q to normalize to %q, got %q", columnType, expected, normalized)
+		}
+	}
+}
+
+func TestNormalizeExpression(t *testing.T) {
+	testCases := map[string]string{
+		"price > 0":                           "(`price` > 0)",
+		"CONCAT(first, ' ', last)":            "concat(`first`,_utf8mb4' ',`last`)",
+		"(a + b) * (c + d)":                   "((`a` + `b`) * (`c` + `d`))",
+		"status IN ('new', 'paid')":           "(`status` in (_utf8mb4\\'new\\',_utf8mb4\\'paid\\'))",
+		"json_valid(`attributes`)":            "json_valid(`attributes`)",
+		"CURRENT_TIMESTAMP":                   "current_timestamp",
+		"(total * 100)":                       "`total` * 100",
+		"YEAR(created_at) >= 2000":            "(year(`created_at`) >= 2000)",
+		"JSON_EXTRACT(attributes, '$.color')": "json_extract(`attributes`,_utf8mb4'$.color')",
+	}
+
+	for configured, reported := range testCases {
+		if normalizeExpression(configured) != normalizeExpression(reported) {
+			t.Errorf("expected %q and %q to be equivalent, got %q and %q", configured, reported, normalizeExpression(configured), normalizeExpression(reported))
+		}
+	}
+
+	different := map[string]string{
+		"(a + b) * c":    "a + (b * c)",
+		"'Foo Bar'":      "'foobar'",
+		"c IN ('A')":     "c IN ('a')",
+		"'2024-01-01 0'": "'2024-01-010'",
+	}
+	for a, b := range different {
+		if normalizeExpression(a) == normalizeExpression(b) {
+			t.Errorf("expected %q and %q not to be equivalent", a, b)
+		}
+	}
+}
+
+func TestImpliedValue(t *testing.T) {
+	testCases := map[string]struct {
+		value    string
+		implied  bool
+		prior    string
+		expected string
+	}{
+		"explicit":          {"latin1", false, "", "latin1"},
+		"implied":           {"utf8mb4", true, "", ""},
+		"implied, set":      {"utf8mb4", true, "utf8mb4", "utf8mb4"},
+		"implied, changed":  {"utf8mb4", true, "latin1", ""},
+		"explicit, changed": {"latin1", false, "utf8mb4", "latin1"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if value := impliedValue(tc.value, tc.implied, tc.prior); value != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, value)
+			}
+		})
+	}
+}
+
+func testAccTableExists(dbName, tableName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		ctx := context.Background()
+		db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+		if err != nil {
+			return err
+		}
+
+		var count int
+		err = db.QueryRow("SELECT COUNT(*) FROM information_schema.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?", dbName, tableName).Scan(&count)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return fmt.Errorf("table %s.%s not found", dbName, tableName)
+		}
+		return nil
+	}
+}
+
+func testAccTableCheckDestroy(dbName, tableName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if err := testAccTableExists(dbName, tableName)(s); err == nil {
+			return fmt.Errorf("table %s.%s still exists", dbName, tableName)
+		}
+		return nil
+	}
+}
+
+func testAccTableConfigBasic(dbName string) string {
+	return fmt.Sprintf(`
+resource "mysql_database" "test" {
+  name = "%s"
+}
+
+resource "mysql_table" "customers" {
+  database = mysql_database.test.name
+  name     = "customers"
+
+  column {
+    name     = "id"
+    type     = "int"
+    nullable = false
+  }
+
+  primary_key = ["id"]
+}
+
+resource "mysql_table" "orders" {
+  database = mysql_database.test.name
+  name     = "orders"
+  comment  = "Orders"
+
+  column {
+    name           = "id"
+    type           = "int"
+    nullable       = false
+    auto_increment = true
+  }
+
+  column {
+    name = "customer_id"
+    type = "int"
+  }
+
+  column {
+    name     = "status"
+    type     = "varchar(16)"
+    nullable = false
+    default  = "new"
+  }
+
+  primary_key = ["id"]
+
+  index {
+    name    = "status_idx"
+    columns = ["status"]
+  }
+
+  foreign_key {
+    name               = "customer_fk"
+    columns            = ["customer_id"]
+    referenced_table   = mysql_table.customers.name
+    referenced_columns = ["id"]
+  }
+
+  auto_increment = 100
+}
+`, dbName)
+}
+
+func testAccTableConfigUpdated(dbName string) string {
+	return fmt.Sprintf(`
+resource "mysql_database" "test" {
+  name = "%s"
+}
+
+resource "mysql_table" "customers" {
+  database = mysql_database.test.name
+  name     = "customers"
+
+  column {
+    name     = "id"
+    type     = "int"
+    nullable = false
+  }
+
+  primary_key = ["id"]
+}
+
+resource "mysql_table" "orders" {
+  database = mysql_database.test.name
+  name     = "orders"
+  comment  = "All orders"
+
+  column {
+    name           = "id"
+    type           = "int"
+    nullable       = false
+    auto_increment = true
+  }
+
+  column {
+    name = "customer_id"
+    type = "int"
+  }
+
+  column {
+    name    = "note"
+    type    = "text"
+    comment = "Free text"
+  }
+
+  column {
+    name     = "status"
+    type     = "varchar(32)"
+    nullable = false
+    default  = "new"
+  }
+
+  primary_key = ["id"]
+
+  index {
+    name    = "status_idx"
+    columns = ["status"]
+  }
+
+  index {
+    name    = "note_idx"
+    type    = "FULLTEXT"
+    columns = ["note"]
+  }
+
+  foreign_key {
+    name               = "customer_fk"
+    columns            = ["customer_id"]
+    referenced_table   = mysql_table.customers.name
+    referenced_columns = ["id"]
+    on_delete          = "CASCADE"
+  }
+
+  auto_increment = 100
+}
+`, dbName)
+}
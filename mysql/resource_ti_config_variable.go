@@ -80,7 +80,7 @@ func CreateOrUpdateConfigVariable(ctx context.Context, d *schema.ResourceData, m
 
 	configQuery = fmt.Sprintf("%s'%s'", configQuery, varValue)
 
-	log.Printf("[DEBUG] SQL: %s\n", configQuery)
+	logSQL(ctx, configQuery)
 
 	_, err = db.ExecContext(ctx, configQuery)
 	if err != nil {
@@ -125,9 +125,9 @@ func ReadConfigVariable(ctx context.Context, d *schema.ResourceData, meta interf
 		configQuery = configQuery + fmt.Sprintf(" AND instance = '%s'", indexParts[2])
 	}
 
-	log.Printf("[DEBUG] SQL: %s\n", configQuery)
+	logSQL(ctx, configQuery)
 
-	err = db.QueryRow(configQuery).Scan(&resType, &resInstance, &resName, &resValue)
+	err = db.QueryRowContext(ctx, configQuery).Scan(&resType, &resInstance, &resName, &resValue)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
 		d.SetId("")
 		return diag.Errorf("error during show config variables: %s", err)
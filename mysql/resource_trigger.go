@@ -0,0 +1,348 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceTrigger() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateTrigger,
+		UpdateContext: UpdateTrigger,
+		ReadContext:   ReadTrigger,
+		DeleteContext: DeleteTrigger,
+		Importer: &schema.ResourceImporter{
+			StateContext: ImportTrigger,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"table": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"timing": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateFunc:     validation.StringInSlice([]string{"BEFORE", "AFTER"}, true),
+				DiffSuppressFunc: suppressCaseDiff,
+			},
+
+			"event": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateFunc:     validation.StringInSlice([]string{"INSERT", "UPDATE", "DELETE"}, true),
+				DiffSuppressFunc: suppressCaseDiff,
+			},
+
+			"body": {
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: suppressProgramBodyDiff,
+			},
+
+			"definer": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ValidateFunc:     validateDefiner,
+				DiffSuppressFunc: suppressDefinerDiff,
+			},
+
+			"follows": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"precedes"},
+			},
+
+			"precedes": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"follows"},
+			},
+		},
+	}
+}
+
+type triggerDefinition struct {
+	Database string
+	Table    string
+	Name     string
+	Timing   string
+	Event    string
+	Body     string
+	Definer  string
+	Follows  string
+	Precedes string
+}
+
+func CreateTrigger(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if !db.Server.Capabilities.StoredPrograms {
+		return diag.Errorf("triggers are not supported by %s %s", db.Server.Flavor, db.Server.VersionString)
+	}
+
+	trigger := expandTrigger(d)
+	stmtSQL := createTriggerSQL(trigger, false)
+	logSQL(ctx, stmtSQL)
+
+	_, err = db.ExecContext(ctx, stmtSQL)
+	if err != nil {
+		return diag.Errorf("failed creating trigger: %v", err)
+	}
+
+	d.SetId(databaseObjectID(trigger.Database, trigger.Name))
+
+	return ReadTrigger(ctx, d, meta)
+}
+
+func UpdateTrigger(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	trigger := expandTrigger(d)
+	previous, err := readTrigger(ctx, db, trigger.Database, trigger.Name)
+	if err != nil {
+		return diag.Errorf("failed reading trigger %s: %v", d.Id(), err)
+	}
+	err = replaceTrigger(ctx, db, trigger, previous)
+	if err != nil {
+		return diag.Errorf("failed replacing trigger: %v", err)
+	}
+
+	return ReadTrigger(ctx, d, meta)
+}
+
+func ReadTrigger(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	database, name, err := parseDatabaseObjectID(d.Id(), "trigger")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	trigger, err := readTrigger(ctx, db, database, name)
+	if err != nil {
+		return diag.Errorf("failed reading trigger %s: %v", d.Id(), err)
+	}
+	if trigger == nil {
+		log.Printf("[WARN] Trigger (%s) not found; removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("database", trigger.Database)
+	d.Set("table", trigger.Table)
+	d.Set("name", trigger.Name)
+	d.Set("timing", trigger.Timing)
+	d.Set("event", trigger.Event)
+	d.Set("body", trigger.Body)
+	d.Set("definer", trigger.Definer)
+	// The server only stores the position of the trigger, so the neighbour
+	// is only read back for the ordering that is configured.
+	if d.Get("follows").(string) != "" {
+		d.Set("follows", trigger.Follows)
+	}
+	if d.Get("precedes").(string) != "" {
+		d.Set("precedes", trigger.Precedes)
+	}
+
+	return nil
+}
+
+func DeleteTrigger(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	stmtSQL := fmt.Sprintf("DROP TRIGGER %s.%s", quoteIdentifier(d.Get("database").(string)), quoteIdentifier(d.Get("name").(string)))
+	logSQL(ctx, stmtSQL)
+
+	_, err = db.ExecContext(ctx, stmtSQL)
+	if err != nil {
+		return diag.Errorf("failed dropping trigger: %v", err)
+	}
+
+	return nil
+}
+
+func ImportTrigger(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	database, name, err := parseDatabaseObjectID(d.Id(), "trigger")
+	if err != nil {
+		return nil, err
+	}
+	d.Set("database", database)
+	d.Set("name", name)
+
+	diags := ReadTrigger(ctx, d, meta)
+	if diags.HasError() {
+		return nil, fmt.Errorf("failed reading trigger: %v", diags)
+	}
+	if d.Id() == "" {
+		return nil, fmt.Errorf("trigger %s.%s not found", database, name)
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// replaceTrigger replaces the trigger, as it can't be altered. MariaDB does
+// so atomically. On MySQL, it's dropped and created again while the table is
+// locked, so no rows are written to it in between. If creating it fails, the
+// previous definition is restored.
+func replaceTrigger(ctx context.Context, db *StatementExecutor, trigger *triggerDefinition, previous *triggerDefinition) error {
+	if db.Server.Flavor == FlavorMariaDB {
+		stmtSQL := createTriggerSQL(trigger, true)
+		logSQL(ctx, stmtSQL)
+		_, err := db.ExecContext(ctx, stmtSQL)
+		return err
+	}
+
+	lockName := databaseObjectLockName("trigger", trigger.Database, trigger.Name)
+	return db.withNamedLock(ctx, lockName, func(conn *StatementConn) error {
+		stmtSQL := fmt.Sprintf("LOCK TABLES %s.%s WRITE", quoteIdentifier(trigger.Database), quoteIdentifier(trigger.Table))
+		logSQL(ctx, stmtSQL)
+		if _, err := conn.ExecContext(ctx, stmtSQL); err != nil {
+			return err
+		}
+		defer func() {
+			// The pooled connection would keep the table locked otherwise.
+			unlockCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), metadataLockQueryTimeout)
+			defer cancel()
+			conn.Conn.ExecContext(unlockCtx, "UNLOCK TABLES")
+		}()
+
+		stmtSQL = fmt.Sprintf("DROP TRIGGER IF EXISTS %s.%s", quoteIdentifier(trigger.Database), quoteIdentifier(trigger.Name))
+		logSQL(ctx, stmtSQL)
+		if _, err := conn.ExecContext(ctx, stmtSQL); err != nil {
+			return err
+		}
+
+		stmtSQL = createTriggerSQL(trigger, false)
+		logSQL(ctx, stmtSQL)
+		_, err := conn.ExecContext(ctx, stmtSQL)
+		if err == nil || previous == nil {
+			return err
+		}
+
+		stmtSQL = createTriggerSQL(previous, false)
+		logSQL(ctx, stmtSQL)
+		if _, restoreErr := conn.ExecContext(ctx, stmtSQL); restoreErr != nil {
+			return fmt.Errorf("%w; restoring the previous definition failed too: %v", err, restoreErr)
+		}
+		return fmt.Errorf("%w; the previous definition was restored", err)
+	})
+}
+
+func expandTrigger(d *schema.ResourceData) *triggerDefinition {
+	return &triggerDefinition{
+		Database: d.Get("database").(string),
+		Table:    d.Get("table").(string),
+		Name:     d.Get("name").(string),
+		Timing:   strings.ToUpper(d.Get("timing").(string)),
+		Event:    strings.ToUpper(d.Get("event").(string)),
+		Body:     d.Get("body").(string),
+		Definer:  d.Get("definer").(string),
+		Follows:  d.Get("follows").(string),
+		Precedes: d.Get("precedes").(string),
+	}
+}
+
+func createTriggerSQL(trigger *triggerDefinition, orReplace bool) string {
+	stmtSQL := "CREATE"
+	if orReplace {
+		stmtSQL += " OR REPLACE"
+	}
+	if trigger.Definer != "" {
+		stmtSQL += " DEFINER = " + formatDefiner(trigger.Definer)
+	}
+	stmtSQL += fmt.Sprintf(" TRIGGER %s.%s %s %s ON %s.%s FOR EACH ROW",
+		quoteIdentifier(trigger.Database), quoteIdentifier(trigger.Name), trigger.Timing, trigger.Event,
+		quoteIdentifier(trigger.Database), quoteIdentifier(trigger.Table))
+	switch {
+	case trigger.Follows != "":
+		stmtSQL += " FOLLOWS " + quoteIdentifier(trigger.Follows)
+	case trigger.Precedes != "":
+		stmtSQL += " PRECEDES " + quoteIdentifier(trigger.Precedes)
+	}
+	return stmtSQL + "\n" + normalizeProgramBody(trigger.Body)
+}
+
+// readTrigger reads the trigger from information_schema, or returns nil if
+// it doesn't exist. Follows and Precedes are the triggers of the same table,
+// timing and event right before and after it.
+func readTrigger(ctx context.Context, db *StatementExecutor, database, name string) (*triggerDefinition, error) {
+	trigger := &triggerDefinition{Database: database, Name: name}
+
+	stmtSQL := `SELECT EVENT_OBJECT_TABLE, ACTION_TIMING, EVENT_MANIPULATION, ACTION_STATEMENT, DEFINER, ACTION_ORDER
+FROM information_schema.TRIGGERS
+WHERE TRIGGER_SCHEMA = ? AND TRIGGER_NAME = ?`
+	logSQL(ctx, stmtSQL)
+
+	var order int64
+	err := db.QueryRowContext(ctx, stmtSQL, database, name).Scan(&trigger.Table, &trigger.Timing, &trigger.Event, &trigger.Body, &trigger.Definer, &order)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	stmtSQL = `SELECT TRIGGER_NAME, ACTION_ORDER
+FROM information_schema.TRIGGERS
+WHERE TRIGGER_SCHEMA = ? AND EVENT_OBJECT_TABLE = ? AND ACTION_TIMING = ? AND EVENT_MANIPULATION = ? AND ACTION_ORDER IN (?, ?)`
+	logSQL(ctx, stmtSQL)
+
+	rows, err := db.QueryContext(ctx, stmtSQL, database, trigger.Table, trigger.Timing, trigger.Event, order-1, order+1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var neighbour string
+		var neighbourOrder int64
+		if err := rows.Scan(&neighbour, &neighbourOrder); err != nil {
+			return nil, err
+		}
+		if neighbourOrder < order {
+			trigger.Follows = neighbour
+		} else {
+			trigger.Precedes = neighbour
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return trigger, nil
+}
@@ -0,0 +1,202 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccTrigger_basic(t *testing.T) {
+	dbName := "tf_trigger_test"
+	resourceName := "mysql_trigger.audit_update"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipTiDB(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccTriggerCheckDestroy(dbName, "audit_update"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTriggerConfig(dbName, "updated"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccTriggerExists(dbName, "audit_update"),
+					resource.TestCheckResourceAttr(resourceName, "id", dbName+".audit_update"),
+					resource.TestCheckResourceAttr(resourceName, "table", "orders"),
+					resource.TestCheckResourceAttr(resourceName, "timing", "AFTER"),
+					resource.TestCheckResourceAttr(resourceName, "event", "UPDATE"),
+					resource.TestCheckResourceAttr(resourceName, "follows", "audit_first"),
+					resource.TestCheckResourceAttrSet(resourceName, "definer"),
+				),
+			},
+			{
+				Config: testAccTriggerConfig(dbName, "changed"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccTriggerExists(dbName, "audit_update"),
+					resource.TestCheckResourceAttr(resourceName, "body", "INSERT INTO audit (order_id, action) VALUES (NEW.id, 'changed')"),
+					resource.TestCheckResourceAttr(resourceName, "follows", "audit_first"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateId:           dbName + ".audit_update",
+				ImportStateVerifyIgnore: []string{"follows"},
+			},
+		},
+	})
+}
+
+func TestCreateTriggerSQL(t *testing.T) {
+	trigger := &triggerDefinition{
+		Database: "shop", Table: "orders", Name: "audit", Timing: "AFTER", Event: "UPDATE",
+		Body: "INSERT INTO audit VALUES (NEW.id);", Definer: "app@%", Follows: "first",
+	}
+
+	testCases := map[string]struct {
+		orReplace bool
+		expected  string
+	}{
+		"create":     {false, "CREATE DEFINER = `app`@`%` TRIGGER `shop`.`audit` AFTER UPDATE ON `shop`.`orders` FOR EACH ROW FOLLOWS `first`\nINSERT INTO audit VALUES (NEW.id)"},
+		"or replace": {true, "CREATE OR REPLACE DEFINER = `app`@`%` TRIGGER `shop`.`audit` AFTER UPDATE ON `shop`.`orders` FOR EACH ROW FOLLOWS `first`\nINSERT INTO audit VALUES (NEW.id)"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if stmt := createTriggerSQL(trigger, tc.orReplace); stmt != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, stmt)
+			}
+		})
+	}
+}
+
+func TestReplaceTrigger(t *testing.T) {
+	ctx := context.Background()
+	trigger := &triggerDefinition{Database: "shop", Table: "orders", Name: "audit", Timing: "BEFORE", Event: "INSERT", Body: "SET NEW.total = 0"}
+
+	testCases := map[string]struct {
+		flavor   ServerFlavor
+		expected []string
+	}{
+		"mysql": {FlavorMySQL, []string{
+			"LOCK TABLES `shop`.`orders` WRITE",
+			"DROP TRIGGER IF EXISTS `shop`.`audit`",
+			"CREATE TRIGGER `shop`.`audit` BEFORE INSERT ON `shop`.`orders` FOR EACH ROW\nSET NEW.total = 0",
+			"UNLOCK TABLES",
+			"DO RELEASE_LOCK(?)",
+		}},
+		"mariadb": {FlavorMariaDB, []string{
+			"CREATE OR REPLACE TRIGGER `shop`.`audit` BEFORE INSERT ON `shop`.`orders` FOR EACH ROW\nSET NEW.total = 0",
+		}},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			connector := &fakeConnector{readOnly: "1"}
+			pool := sql.OpenDB(connector)
+			defer pool.Close()
+
+			db := newStatementExecutor(ctx, pool, StatementOptions{})
+			db.Server = &ServerInfo{Flavor: tc.flavor}
+			if err := replaceTrigger(ctx, db, trigger, nil); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(connector.executed, tc.expected) {
+				t.Errorf("expected %q, got %q", tc.expected, connector.executed)
+			}
+		})
+	}
+}
+
+func testAccTriggerExists(dbName, name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		ctx := context.Background()
+		db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+		if err != nil {
+			return err
+		}
+
+		var count int
+		err = db.QueryRow("SELECT COUNT(*) FROM information_schema.TRIGGERS WHERE TRIGGER_SCHEMA = ? AND TRIGGER_NAME = ?", dbName, name).Scan(&count)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return fmt.Errorf("trigger %s.%s not found", dbName, name)
+		}
+		return nil
+	}
+}
+
+func testAccTriggerCheckDestroy(dbName, name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if err := testAccTriggerExists(dbName, name)(s); err == nil {
+			return fmt.Errorf("trigger %s.%s still exists", dbName, name)
+		}
+		return nil
+	}
+}
+
+func testAccTriggerConfig(dbName, action string) string {
+	return fmt.Sprintf(`
+resource "mysql_database" "test" {
+  name = "%s"
+}
+
+resource "mysql_table" "orders" {
+  database = mysql_database.test.name
+  name     = "orders"
+
+  column {
+    name     = "id"
+    type     = "int"
+    nullable = false
+  }
+
+  primary_key = ["id"]
+}
+
+resource "mysql_table" "audit" {
+  database = mysql_database.test.name
+  name     = "audit"
+
+  column {
+    name = "order_id"
+    type = "int"
+  }
+
+  column {
+    name = "action"
+    type = "varchar(16)"
+  }
+}
+
+resource "mysql_trigger" "audit_first" {
+  database = mysql_database.test.name
+  table    = mysql_table.orders.name
+  name     = "audit_first"
+  timing   = "AFTER"
+  event    = "UPDATE"
+  body     = "INSERT INTO audit (order_id, action) VALUES (NEW.id, 'first')"
+
+  depends_on = [mysql_table.audit]
+}
+
+resource "mysql_trigger" "audit_update" {
+  database = mysql_database.test.name
+  table    = mysql_table.orders.name
+  name     = "audit_update"
+  timing   = "AFTER"
+  event    = "UPDATE"
+  follows  = mysql_trigger.audit_first.name
+  body     = "INSERT INTO audit (order_id, action) VALUES (NEW.id, '%s')"
+}
+`, dbName, action)
+}
@@ -10,7 +10,6 @@ import (
 	"strings"
 
 	"github.com/hashicorp/go-cty/cty"
-	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -60,6 +59,15 @@ func resourceUser() *schema.Resource {
 				}
 			}
 
+			// Validate the password policy is supported by the server version
+			for _, attribute := range passwordPolicyAttributes {
+				if isAttributeSet(d.GetRawConfig(), attribute) {
+					if err := checkPasswordPolicySupport(ctx, meta, attribute); err != nil {
+						return err
+					}
+				}
+			}
+
 			return nil
 		},
 
@@ -187,38 +195,86 @@ func resourceUser() *schema.Resource {
 				ValidateFunc: validation.FloatAtLeast(0),
 				Description:  "Maximum execution time for statements in seconds (0 = unlimited). Supports fractional values (e.g., 0.01 for 10ms, 30.5 for 30.5s). Only supported on MariaDB 10.1.1+, not MySQL.",
 			},
+
+			"password_expire": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateFunc:     validation.StringMatch(regexp.MustCompile(`^(?i)(DEFAULT|NEVER|[1-9]\d*)$`), "must be DEFAULT, NEVER or a number of days"),
+				DiffSuppressFunc: suppressCaseDiff,
+				Description:      "How often the password must be changed: DEFAULT for the server's default_password_lifetime, NEVER, or a number of days. Supported on MySQL 5.7.6+ and MariaDB 10.4.3+.",
+			},
+
+			"password_history": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Number of previous passwords that can't be reused. Supported on MySQL 8.0.3+.",
+			},
+
+			"password_reuse_interval": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Number of days before a previous password can be reused. Supported on MySQL 8.0.3+.",
+			},
+
+			"password_require_current": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateFunc:     validation.StringInSlice([]string{"DEFAULT", "OPTIONAL", "REQUIRED"}, true),
+				DiffSuppressFunc: suppressCaseDiff,
+				Description:      "Whether changing the password requires the current one: DEFAULT for the server's password_require_current, OPTIONAL or REQUIRED. Supported on MySQL 8.0.13+.",
+			},
+
+			"failed_login_attempts": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntBetween(0, 32767),
+				Description:  "Number of consecutive failed logins that lock the account (0 = never). Supported on MySQL 8.0.19+.",
+			},
+
+			"password_lock_time": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateFunc:     validation.StringMatch(regexp.MustCompile(`^(?i)(UNBOUNDED|\d+)$`), "must be UNBOUNDED or a number of days"),
+				DiffSuppressFunc: suppressCaseDiff,
+				Description:      "Number of days the account stays locked after too many failed logins, or UNBOUNDED. Supported on MySQL 8.0.19+.",
+			},
 		},
 	}
 }
 
 func checkRetainCurrentPasswordSupport(ctx context.Context, meta interface{}) error {
-	ver, _ := version.NewVersion("8.0.14")
-	if getVersionFromMeta(ctx, meta).LessThan(ver) {
+	serverInfo, err := getServerInfoFromMeta(ctx, meta)
+	if err != nil {
+		return err
+	}
+
+	if !serverInfo.Capabilities.DualPasswords {
 		return errors.New("MySQL version must be at least 8.0.14")
 	}
 	return nil
 }
 
 func checkDiscardOldPasswordSupport(ctx context.Context, meta interface{}) error {
-	ver, _ := version.NewVersion("8.0.14")
-	if getVersionFromMeta(ctx, meta).LessThan(ver) {
+	serverInfo, err := getServerInfoFromMeta(ctx, meta)
+	if err != nil {
+		return err
+	}
+
+	if !serverInfo.Capabilities.DualPasswords {
 		return errors.New("MySQL version must be at least 8.0.14")
 	}
 	return nil
 }
 
 func checkMaxUserConnectionsSupport(ctx context.Context, meta interface{}) error {
-	db, err := getDatabaseFromMeta(ctx, meta)
+	serverInfo, err := getServerInfoFromMeta(ctx, meta)
 	if err != nil {
 		return err
 	}
 
-	isTiDB, _, _, err := serverTiDB(db)
-	if err != nil {
-		return err
-	}
-
-	if isTiDB {
+	if !serverInfo.Capabilities.MaxUserConnections {
 		return errors.New("MAX_USER_CONNECTIONS is not supported on TiDB")
 	}
 
@@ -226,29 +282,187 @@ func checkMaxUserConnectionsSupport(ctx context.Context, meta interface{}) error
 }
 
 func checkMaxStatementTimeSupport(ctx context.Context, meta interface{}) error {
-	db, err := getDatabaseFromMeta(ctx, meta)
+	serverInfo, err := getServerInfoFromMeta(ctx, meta)
 	if err != nil {
 		return err
 	}
 
-	isMariaDB, err := serverMariaDB(db)
+	if serverInfo.Flavor != FlavorMariaDB {
+		return errors.New("MAX_STATEMENT_TIME is only supported on MariaDB 10.1.1+, not MySQL")
+	}
+
+	if !serverInfo.Capabilities.MaxStatementTime {
+		return fmt.Errorf("MAX_STATEMENT_TIME requires MariaDB 10.1.1 or newer (current version: %s)", serverInfo.Version.String())
+	}
+
+	return nil
+}
+
+// passwordPolicyAttributes are the attributes setting password and lock
+// options of CREATE USER and ALTER USER.
+var passwordPolicyAttributes = []string{
+	"password_expire",
+	"password_history",
+	"password_reuse_interval",
+	"password_require_current",
+	"failed_login_attempts",
+	"password_lock_time",
+}
+
+// passwordPolicyResets restore the server's defaults for attributes that are
+// removed from the configuration.
+var passwordPolicyResets = map[string]string{
+	"password_expire":          "PASSWORD EXPIRE DEFAULT",
+	"password_history":         "PASSWORD HISTORY DEFAULT",
+	"password_reuse_interval":  "PASSWORD REUSE INTERVAL DEFAULT",
+	"password_require_current": "PASSWORD REQUIRE CURRENT DEFAULT",
+	"failed_login_attempts":    "FAILED_LOGIN_ATTEMPTS 0",
+	"password_lock_time":       "PASSWORD_LOCK_TIME 0",
+}
+
+func checkPasswordPolicySupport(ctx context.Context, meta interface{}, attribute string) error {
+	serverInfo, err := getServerInfoFromMeta(ctx, meta)
 	if err != nil {
 		return err
 	}
 
-	if !isMariaDB {
-		return errors.New("MAX_STATEMENT_TIME is only supported on MariaDB 10.1.1+, not MySQL")
+	capabilities := serverInfo.Capabilities
+	var supported bool
+	var requirement string
+	switch attribute {
+	case "password_expire":
+		supported, requirement = capabilities.PasswordExpiration, "MySQL 5.7.6 or MariaDB 10.4.3"
+	case "password_history", "password_reuse_interval":
+		supported, requirement = capabilities.PasswordReuse, "MySQL 8.0.3"
+	case "password_require_current":
+		supported, requirement = capabilities.PasswordRequireCurrent, "MySQL 8.0.13"
+	case "failed_login_attempts", "password_lock_time":
+		supported, requirement = capabilities.FailedLoginTracking, "MySQL 8.0.19"
 	}
 
-	// Check MariaDB version
-	currentVer := getVersionFromMeta(ctx, meta)
-	minVer, _ := version.NewVersion("10.1.1")
+	if !supported {
+		return fmt.Errorf("%s requires %s or newer (current version: %s)", attribute, requirement, serverInfo.VersionString)
+	}
+	return nil
+}
 
-	if currentVer.LessThan(minVer) {
-		return fmt.Errorf("MAX_STATEMENT_TIME requires MariaDB 10.1.1 or newer (current version: %s)", currentVer.String())
+// passwordPolicyClause returns the clause of CREATE USER and ALTER USER
+// setting the attribute to value.
+func passwordPolicyClause(attribute string, value interface{}) string {
+	switch attribute {
+	case "password_expire":
+		expire := strings.ToUpper(value.(string))
+		if expire == "DEFAULT" || expire == "NEVER" {
+			return "PASSWORD EXPIRE " + expire
+		}
+		return fmt.Sprintf("PASSWORD EXPIRE INTERVAL %s DAY", expire)
+	case "password_history":
+		return fmt.Sprintf("PASSWORD HISTORY %d", value.(int))
+	case "password_reuse_interval":
+		return fmt.Sprintf("PASSWORD REUSE INTERVAL %d DAY", value.(int))
+	case "password_require_current":
+		requireCurrent := strings.ToUpper(value.(string))
+		if requireCurrent == "REQUIRED" {
+			return "PASSWORD REQUIRE CURRENT"
+		}
+		return "PASSWORD REQUIRE CURRENT " + requireCurrent
+	case "failed_login_attempts":
+		return fmt.Sprintf("FAILED_LOGIN_ATTEMPTS %d", value.(int))
+	case "password_lock_time":
+		return "PASSWORD_LOCK_TIME " + strings.ToUpper(value.(string))
 	}
+	return ""
+}
 
-	return nil
+// getPasswordPolicyClauses returns the clauses for the configured password
+// policy. With changesOnly, only changed attributes are returned, and removed
+// ones are reset to the server's defaults.
+func getPasswordPolicyClauses(ctx context.Context, d *schema.ResourceData, meta interface{}, changesOnly bool) ([]string, error) {
+	var clauses []string
+	for _, attribute := range passwordPolicyAttributes {
+		// 0 is a valid setting, so whether an attribute is set is told from
+		// the configuration rather than its value.
+		set := isAttributeSet(d.GetRawConfig(), attribute)
+		if changesOnly && !d.HasChange(attribute) && set == isAttributeSet(d.GetRawState(), attribute) {
+			continue
+		}
+		if set {
+			if err := checkPasswordPolicySupport(ctx, meta, attribute); err != nil {
+				return nil, err
+			}
+			clauses = append(clauses, passwordPolicyClause(attribute, d.Get(attribute)))
+		} else if changesOnly {
+			clauses = append(clauses, passwordPolicyResets[attribute])
+		}
+	}
+	return clauses, nil
+}
+
+var (
+	passwordExpireRegex         = regexp.MustCompile(`\bPASSWORD EXPIRE (DEFAULT|NEVER|INTERVAL (\d+) DAY)`)
+	passwordHistoryRegex        = regexp.MustCompile(`\bPASSWORD HISTORY (\d+)`)
+	passwordReuseIntervalRegex  = regexp.MustCompile(`\bPASSWORD REUSE INTERVAL (\d+) DAY`)
+	passwordRequireCurrentRegex = regexp.MustCompile(`\bPASSWORD REQUIRE CURRENT( DEFAULT| OPTIONAL)?\b`)
+	failedLoginAttemptsRegex    = regexp.MustCompile(`\bFAILED_LOGIN_ATTEMPTS (\d+)`)
+	passwordLockTimeRegex       = regexp.MustCompile(`\bPASSWORD_LOCK_TIME (\d+|UNBOUNDED)`)
+)
+
+// isAttributeSet tells whether the attribute is set in a raw configuration
+// or state, which unlike GetOk tells a zero value from an unset one.
+func isAttributeSet(raw cty.Value, attribute string) bool {
+	return !raw.IsNull() && raw.IsKnown() && !raw.GetAttr(attribute).IsNull()
+}
+
+// parsePasswordPolicy reads the password policy attributes from the output of
+// SHOW CREATE USER. Clauses that are left out have the server's defaults.
+func parsePasswordPolicy(createUserStmt string) map[string]interface{} {
+	policy := map[string]interface{}{
+		"password_expire":          "DEFAULT",
+		"password_history":         0,
+		"password_reuse_interval":  0,
+		"password_require_current": "DEFAULT",
+		"failed_login_attempts":    0,
+		"password_lock_time":       "0",
+	}
+
+	if m := passwordExpireRegex.FindStringSubmatch(createUserStmt); m != nil {
+		if m[2] != "" {
+			policy["password_expire"] = m[2]
+		} else {
+			policy["password_expire"] = m[1]
+		}
+	}
+	if m := passwordHistoryRegex.FindStringSubmatch(createUserStmt); m != nil {
+		policy["password_history"], _ = strconv.Atoi(m[1])
+	}
+	if m := passwordReuseIntervalRegex.FindStringSubmatch(createUserStmt); m != nil {
+		policy["password_reuse_interval"], _ = strconv.Atoi(m[1])
+	}
+	if m := passwordRequireCurrentRegex.FindStringSubmatch(createUserStmt); m != nil {
+		if m[1] != "" {
+			policy["password_require_current"] = strings.TrimSpace(m[1])
+		} else {
+			policy["password_require_current"] = "REQUIRED"
+		}
+	}
+	if m := failedLoginAttemptsRegex.FindStringSubmatch(createUserStmt); m != nil {
+		policy["failed_login_attempts"], _ = strconv.Atoi(m[1])
+	}
+	if m := passwordLockTimeRegex.FindStringSubmatch(createUserStmt); m != nil {
+		policy["password_lock_time"] = m[1]
+	}
+
+	return policy
+}
+
+// setPasswordPolicy sets the password policy attributes that are managed, so
+// changes made outside of Terraform show up as drift.
+func setPasswordPolicy(d *schema.ResourceData, createUserStmt string) {
+	for attribute, value := range parsePasswordPolicy(createUserStmt) {
+		if isAttributeSet(d.GetRawState(), attribute) {
+			d.Set(attribute, value)
+		}
+	}
 }
 
 func CreateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -257,6 +471,8 @@ func CreateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 		return diag.FromErr(err)
 	}
 
+	capabilities := db.Server.Capabilities
+
 	var authStm string
 	var auth string
 	var createObj = "USER"
@@ -360,11 +576,10 @@ func CreateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 		stmtSQL += fmt.Sprintf(" IDENTIFIED BY %s", quoteString(password))
 	}
 
-	requiredVersion, _ := version.NewVersion("5.7.0")
 	var updateStmtSql string
 	var updateArgs []interface{}
 
-	if getVersionFromMeta(ctx, meta).GreaterThan(requiredVersion) && d.Get("tls_option").(string) != "" {
+	if capabilities.UserTLSOptions && d.Get("tls_option").(string) != "" {
 		if createObj == "AADUSER" {
 			updateStmtSql = fmt.Sprintf("ALTER USER %s REQUIRE %s", formatUserIdentifier(user, host), d.Get("tls_option").(string))
 			updateArgs = []interface{}{}
@@ -396,21 +611,22 @@ func CreateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 		}
 
 		// MySQL 5.7.6+ supports CREATE USER ... WITH for resource limits
-		createUserWithVersion, _ := version.NewVersion("5.7.6")
-		if len(resourceLimits) > 0 && getVersionFromMeta(ctx, meta).GreaterThanOrEqual(createUserWithVersion) {
+		if len(resourceLimits) > 0 && capabilities.AlterUser {
 			stmtSQL += " WITH " + strings.Join(resourceLimits, " ")
 		}
 	}
 
-	// Log statement with sensitive values redacted
-	logStmt := stmtSQL
-	if password != "" {
-		logStmt = strings.Replace(logStmt, quoteString(password), "<SENSITIVE>", -1)
+	// Password and lock options follow the resource limits. CREATE AADUSER
+	// doesn't take them, so they are set with ALTER USER afterwards.
+	passwordPolicy, err := getPasswordPolicyClauses(ctx, d, meta, false)
+	if err != nil {
+		return diag.FromErr(err)
 	}
-	if hashed != "" {
-		logStmt = strings.Replace(logStmt, quoteString(hashed), "<SENSITIVE>", -1)
+	if len(passwordPolicy) > 0 && createObj != "AADUSER" {
+		stmtSQL += " " + strings.Join(passwordPolicy, " ")
 	}
-	log.Println("[DEBUG] Executing statement:", logStmt)
+
+	logSQL(ctx, stmtSQL)
 
 	_, err = db.ExecContext(ctx, stmtSQL)
 	if err != nil {
@@ -418,13 +634,12 @@ func CreateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 	}
 
 	// For MySQL < 5.7.6, use GRANT USAGE to set resource limits after CREATE USER
-	createUserWithVersion, _ := version.NewVersion("5.7.6")
-	if createObj != "AADUSER" && len(resourceLimits) > 0 && getVersionFromMeta(ctx, meta).LessThan(createUserWithVersion) {
+	if createObj != "AADUSER" && len(resourceLimits) > 0 && !capabilities.AlterUser {
 		grantStmtSQL := fmt.Sprintf("GRANT USAGE ON *.* TO %s WITH %s",
 			formatUserIdentifier(user, host),
 			strings.Join(resourceLimits, " "))
 
-		log.Println("[DEBUG] Executing statement:", grantStmtSQL)
+		logSQL(ctx, grantStmtSQL)
 		_, err = db.ExecContext(ctx, grantStmtSQL)
 		if err != nil {
 			return diag.Errorf("failed setting user resource limits: %v", err)
@@ -435,7 +650,7 @@ func CreateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 	d.SetId(userId)
 
 	if updateStmtSql != "" {
-		log.Println("[DEBUG] Executing statement:", updateStmtSql, "args:", updateArgs)
+		logSQL(ctx, updateStmtSql)
 		_, err = db.ExecContext(ctx, updateStmtSql, updateArgs...)
 		if err != nil {
 			d.Set("tls_option", "")
@@ -443,6 +658,15 @@ func CreateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 		}
 	}
 
+	if len(passwordPolicy) > 0 && createObj == "AADUSER" {
+		stmtSQL := fmt.Sprintf("ALTER USER %s %s", formatUserIdentifier(user, host), strings.Join(passwordPolicy, " "))
+		logSQL(ctx, stmtSQL)
+		_, err = db.ExecContext(ctx, stmtSQL)
+		if err != nil {
+			return diag.Errorf("failed setting user password policy: %v", err)
+		}
+	}
+
 	return nil
 }
 
@@ -452,8 +676,12 @@ func getSetPasswordStatement(ctx context.Context, meta interface{}, user, host,
 	}
 
 	/* ALTER USER syntax introduced in MySQL 5.7.6 deprecates SET PASSWORD (GH-8230) */
-	ver, _ := version.NewVersion("5.7.6")
-	if getVersionFromMeta(ctx, meta).LessThan(ver) {
+	serverInfo, err := getServerInfoFromMeta(ctx, meta)
+	if err != nil {
+		return "", err
+	}
+
+	if !serverInfo.Capabilities.AlterUser {
 		return fmt.Sprintf("SET PASSWORD FOR %s = PASSWORD(%s)", formatUserIdentifier(user, host), quoteString(password)), nil
 	}
 
@@ -466,6 +694,8 @@ func UpdateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 		return diag.FromErr(err)
 	}
 
+	capabilities := db.Server.Capabilities
+
 	var auth string
 	if v, ok := d.GetOk("auth_plugin"); ok {
 		auth = v.(string)
@@ -492,7 +722,7 @@ func UpdateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 				authString,
 				d.Get("tls_option").(string))
 
-			log.Println("[DEBUG] Executing query:", stmtSQL)
+			logSQL(ctx, stmtSQL)
 			_, err := db.ExecContext(ctx, stmtSQL)
 			if err != nil {
 				return diag.Errorf("failed running query: %v", err)
@@ -510,7 +740,7 @@ func UpdateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 			stmtSQL = fmt.Sprintf("ALTER USER %s DISCARD OLD PASSWORD",
 				formatUserIdentifier(d.Get("user").(string), d.Get("host").(string)))
 
-			log.Println("[DEBUG] Executing query:", stmtSQL)
+			logSQL(ctx, stmtSQL)
 			_, err := db.ExecContext(ctx, stmtSQL)
 			if err != nil {
 				return diag.Errorf("failed running query: %v", err)
@@ -549,24 +779,21 @@ func UpdateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 			return diag.Errorf("failed getting change password statement: %v", err)
 		}
 
-		// Log with password redacted
-		logStmt := strings.Replace(stmtSQL, quoteString(newpw.(string)), "<SENSITIVE>", -1)
-		log.Println("[DEBUG] Executing query:", logStmt)
+		logSQL(ctx, stmtSQL)
 		_, err = db.ExecContext(ctx, stmtSQL)
 		if err != nil {
 			return diag.Errorf("failed changing password: %v", err)
 		}
 	}
 
-	requiredVersion, _ := version.NewVersion("5.7.0")
-	if d.HasChange("tls_option") && getVersionFromMeta(ctx, meta).GreaterThan(requiredVersion) {
+	if d.HasChange("tls_option") && capabilities.UserTLSOptions {
 		var stmtSQL string
 
 		stmtSQL = fmt.Sprintf("ALTER USER %s REQUIRE %s",
 			formatUserIdentifier(d.Get("user").(string), d.Get("host").(string)),
 			d.Get("tls_option").(string))
 
-		log.Println("[DEBUG] Executing query:", stmtSQL)
+		logSQL(ctx, stmtSQL)
 		_, err := db.ExecContext(ctx, stmtSQL)
 		if err != nil {
 			return diag.Errorf("failed setting require tls option: %v", err)
@@ -589,11 +816,7 @@ func UpdateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 		} else if d.HasChange("max_user_connections") {
 			// Field was removed from config, reset to 0 (unlimited)
 			// Only reset if we're not on TiDB (which doesn't support this feature)
-			isTiDBVal, _, _, err := serverTiDB(db)
-			if err != nil {
-				return diag.FromErr(err)
-			}
-			if !isTiDBVal {
+			if capabilities.MaxUserConnections {
 				resourceLimits = append(resourceLimits, "MAX_USER_CONNECTIONS 0")
 			} else {
 				return diag.Errorf("cannot reset max_user_connections on TiDB: MAX_USER_CONNECTIONS is not supported on TiDB")
@@ -610,11 +833,7 @@ func UpdateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 		} else if d.HasChange("max_statement_time") {
 			// Field was removed from config, reset to 0 (unlimited)
 			// Only reset if we're on MariaDB (no need to check version, just database type)
-			isMariaDBVal, err := serverMariaDB(db)
-			if err != nil {
-				return diag.FromErr(err)
-			}
-			if isMariaDBVal {
+			if db.Server.Flavor == FlavorMariaDB {
 				resourceLimits = append(resourceLimits, "MAX_STATEMENT_TIME 0")
 			}
 		}
@@ -624,8 +843,7 @@ func UpdateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 
 			// MySQL versions before 5.7.6 don't support ALTER USER with WITH clause
 			// Use GRANT USAGE instead for older versions
-			alterUserVersion, _ := version.NewVersion("5.7.6")
-			if getVersionFromMeta(ctx, meta).LessThan(alterUserVersion) {
+			if !capabilities.AlterUser {
 				// MySQL 5.6 and earlier: use GRANT USAGE
 				stmtSQL = fmt.Sprintf("GRANT USAGE ON *.* TO %s WITH %s",
 					formatUserIdentifier(d.Get("user").(string), d.Get("host").(string)),
@@ -637,7 +855,7 @@ func UpdateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 					strings.Join(resourceLimits, " "))
 			}
 
-			log.Println("[DEBUG] Executing query:", stmtSQL)
+			logSQL(ctx, stmtSQL)
 			_, err := db.ExecContext(ctx, stmtSQL)
 			if err != nil {
 				return diag.Errorf("failed setting user resource limits: %v", err)
@@ -645,6 +863,24 @@ func UpdateUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 		}
 	}
 
+	// Handle password policy changes, removed attributes are reset to the
+	// server's defaults
+	passwordPolicy, err := getPasswordPolicyClauses(ctx, d, meta, true)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if len(passwordPolicy) > 0 {
+		stmtSQL := fmt.Sprintf("ALTER USER %s %s",
+			formatUserIdentifier(d.Get("user").(string), d.Get("host").(string)),
+			strings.Join(passwordPolicy, " "))
+
+		logSQL(ctx, stmtSQL)
+		_, err := db.ExecContext(ctx, stmtSQL)
+		if err != nil {
+			return diag.Errorf("failed setting user password policy: %v", err)
+		}
+	}
+
 	return nil
 }
 
@@ -676,8 +912,9 @@ func ReadUser(ctx context.Context, d *schema.ResourceData, meta interface{}) dia
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	requiredVersion, _ := version.NewVersion("5.7.0")
-	if getVersionFromMeta(ctx, meta).GreaterThan(requiredVersion) {
+
+	capabilities := db.Server.Capabilities
+	if capabilities.ShowCreateUser {
 		// Skip setting print_identified_with_as_hex if auth_plugin is aad_auth
 		if d.Get("auth_plugin") != "aad_auth" {
 			_, err := db.ExecContext(ctx, "SET print_identified_with_as_hex = ON")
@@ -773,6 +1010,7 @@ func ReadUser(ctx context.Context, d *schema.ResourceData, meta interface{}) dia
 				parseWithClauseSetting(d, withClause, "max_user_connections", "MAX_USER_CONNECTIONS", false)
 				parseWithClauseSetting(d, withClause, "max_statement_time", "MAX_STATEMENT_TIME", true)
 			}
+			setPasswordPolicy(d, createUserStmt)
 
 			return nil
 		}
@@ -789,6 +1027,7 @@ func ReadUser(ctx context.Context, d *schema.ResourceData, meta interface{}) dia
 				parseWithClauseSetting(d, withClause, "max_user_connections", "MAX_USER_CONNECTIONS", false)
 				parseWithClauseSetting(d, withClause, "max_statement_time", "MAX_STATEMENT_TIME", true)
 			}
+			setPasswordPolicy(d, createUserStmt)
 
 			return nil
 		}
@@ -798,7 +1037,7 @@ func ReadUser(ctx context.Context, d *schema.ResourceData, meta interface{}) dia
 		stmtSQL := fmt.Sprintf("SELECT USER FROM mysql.user WHERE USER='%s'",
 			d.Get("user").(string))
 
-		log.Println("[DEBUG] Executing statement:", stmtSQL)
+		logSQL(ctx, stmtSQL)
 
 		rows, err := db.QueryContext(ctx, stmtSQL)
 		if err != nil {
@@ -825,7 +1064,7 @@ func DeleteUser(ctx context.Context, d *schema.ResourceData, meta interface{}) d
 
 	stmtSQL := fmt.Sprintf("DROP USER %s", formatUserIdentifier(d.Get("user").(string), d.Get("host").(string)))
 
-	log.Println("[DEBUG] Executing statement:", stmtSQL)
+	logSQL(ctx, stmtSQL)
 
 	_, err = db.ExecContext(ctx, stmtSQL)
 
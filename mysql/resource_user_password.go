@@ -4,12 +4,10 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 
 	"github.com/gofrs/uuid"
-	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
@@ -78,9 +76,7 @@ func SetUserPassword(ctx context.Context, d *schema.ResourceData, meta interface
 		return diag.Errorf("failed getting password statement: %v", err)
 	}
 
-	// Log with password redacted
-	logStmt := strings.Replace(stmtSQL, quoteString(passwordStr), "<SENSITIVE>", -1)
-	log.Println("[DEBUG] Executing statement:", logStmt)
+	logSQL(ctx, stmtSQL)
 
 	_, err = db.ExecContext(ctx, stmtSQL)
 	if err != nil {
@@ -94,9 +90,12 @@ func SetUserPassword(ctx context.Context, d *schema.ResourceData, meta interface
 }
 
 func canReadPassword(ctx context.Context, meta interface{}) (bool, error) {
-	serverVersion := getVersionFromMeta(ctx, meta)
-	ver, _ := version.NewVersion("8.0.0")
-	return serverVersion.LessThan(ver), nil
+	serverInfo, err := getServerInfoFromMeta(ctx, meta)
+	if err != nil {
+		return false, err
+	}
+
+	return serverInfo.Capabilities.ReadablePasswords, nil
 }
 
 func ReadUserPassword(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
@@ -7,8 +7,10 @@ import (
 	"fmt"
 	"log"
 	"regexp"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
@@ -1155,3 +1157,204 @@ resource "mysql_user" "test" {
     max_user_connections = 10
 }
 `
+
+// Password policy tests
+func TestAccUser_passwordPolicy(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckSkipMariaDB(t)
+			testAccPreCheckSkipTiDB(t)
+			testAccPreCheckSkipNotMySQLVersionMin(t, "8.0.19")
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccUserCheckDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccUserConfig_passwordPolicy,
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "password_expire", "90"),
+					resource.TestCheckResourceAttr("mysql_user.test", "password_history", "5"),
+					testAccUserPasswordPolicy("policy_user", "%", "password_expire", "90"),
+					testAccUserPasswordPolicy("policy_user", "%", "password_history", 5),
+					testAccUserPasswordPolicy("policy_user", "%", "password_reuse_interval", 365),
+					testAccUserPasswordPolicy("policy_user", "%", "password_require_current", "REQUIRED"),
+					testAccUserPasswordPolicy("policy_user", "%", "failed_login_attempts", 3),
+					testAccUserPasswordPolicy("policy_user", "%", "password_lock_time", "2"),
+				),
+			},
+			{
+				Config: testAccUserConfig_passwordPolicyUpdated,
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "password_expire", "NEVER"),
+					resource.TestCheckResourceAttr("mysql_user.test", "password_lock_time", "UNBOUNDED"),
+					testAccUserPasswordPolicy("policy_user", "%", "password_expire", "NEVER"),
+					testAccUserPasswordPolicy("policy_user", "%", "password_history", 0),
+					testAccUserPasswordPolicy("policy_user", "%", "password_lock_time", "UNBOUNDED"),
+				),
+			},
+			{
+				Config: testAccUserConfig_passwordPolicyZero,
+				Check: resource.ComposeTestCheckFunc(
+					testAccUserExists("mysql_user.test"),
+					resource.TestCheckResourceAttr("mysql_user.test", "password_history", "0"),
+					resource.TestCheckResourceAttr("mysql_user.test", "failed_login_attempts", "0"),
+					testAccUserCreateStatementContains("policy_user", "%", "PASSWORD HISTORY 0 "),
+					testAccUserCreateStatementContains("policy_user", "%", "PASSWORD REUSE INTERVAL 0 DAY"),
+				),
+			},
+		},
+	})
+}
+
+func TestIsAttributeSet(t *testing.T) {
+	config := cty.ObjectVal(map[string]cty.Value{
+		"password_history":      cty.NumberIntVal(0),
+		"failed_login_attempts": cty.NullVal(cty.Number),
+	})
+	if !isAttributeSet(config, "password_history") {
+		t.Error("expected password_history = 0 to be set")
+	}
+	if isAttributeSet(config, "failed_login_attempts") {
+		t.Error("expected failed_login_attempts not to be set")
+	}
+	if isAttributeSet(cty.NullVal(config.Type()), "password_history") {
+		t.Error("expected nothing to be set without a configuration")
+	}
+}
+
+func TestParsePasswordPolicy(t *testing.T) {
+	testCases := map[string]struct {
+		createUserStmt string
+		expected       map[string]interface{}
+	}{
+		"defaults": {
+			"CREATE USER `jdoe`@`%` IDENTIFIED WITH 'caching_sha2_password' REQUIRE NONE PASSWORD EXPIRE DEFAULT ACCOUNT UNLOCK PASSWORD HISTORY DEFAULT PASSWORD REUSE INTERVAL DEFAULT PASSWORD REQUIRE CURRENT DEFAULT",
+			map[string]interface{}{
+				"password_expire":          "DEFAULT",
+				"password_history":         0,
+				"password_reuse_interval":  0,
+				"password_require_current": "DEFAULT",
+				"failed_login_attempts":    0,
+				"password_lock_time":       "0",
+			},
+		},
+		"mysql policy": {
+			"CREATE USER `jdoe`@`%` IDENTIFIED WITH 'caching_sha2_password' REQUIRE NONE PASSWORD EXPIRE INTERVAL 90 DAY ACCOUNT UNLOCK PASSWORD HISTORY 5 PASSWORD REUSE INTERVAL 365 DAY PASSWORD REQUIRE CURRENT FAILED_LOGIN_ATTEMPTS 3 PASSWORD_LOCK_TIME UNBOUNDED",
+			map[string]interface{}{
+				"password_expire":          "90",
+				"password_history":         5,
+				"password_reuse_interval":  365,
+				"password_require_current": "REQUIRED",
+				"failed_login_attempts":    3,
+				"password_lock_time":       "UNBOUNDED",
+			},
+		},
+		"mariadb": {
+			"CREATE USER `jdoe`@`%` IDENTIFIED BY PASSWORD '*2470C0C06DEE42FD1618BB99005ADCA2EC9D1E19' PASSWORD EXPIRE NEVER",
+			map[string]interface{}{
+				"password_expire":          "NEVER",
+				"password_history":         0,
+				"password_reuse_interval":  0,
+				"password_require_current": "DEFAULT",
+				"failed_login_attempts":    0,
+				"password_lock_time":       "0",
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			policy := parsePasswordPolicy(tc.createUserStmt)
+			for attribute, expected := range tc.expected {
+				if policy[attribute] != expected {
+					t.Errorf("expected %s %v, got %v", attribute, expected, policy[attribute])
+				}
+			}
+		})
+	}
+}
+
+// Helper function to verify a password policy attribute in SHOW CREATE USER
+func testAccUserPasswordPolicy(user, host, attribute string, expected interface{}) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		createUserStmt, err := testAccShowCreateUser(user, host)
+		if err != nil {
+			return err
+		}
+		if value := parsePasswordPolicy(createUserStmt)[attribute]; value != expected {
+			return fmt.Errorf("expected %s %v, got %v in %q", attribute, expected, value, createUserStmt)
+		}
+		return nil
+	}
+}
+
+// testAccUserCreateStatementContains checks a clause is set explicitly,
+// which parsePasswordPolicy doesn't tell from the server's default.
+func testAccUserCreateStatementContains(user, host, clause string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		createUserStmt, err := testAccShowCreateUser(user, host)
+		if err != nil {
+			return err
+		}
+		if !strings.Contains(createUserStmt, clause) {
+			return fmt.Errorf("expected %q in %q", clause, createUserStmt)
+		}
+		return nil
+	}
+}
+
+func testAccShowCreateUser(user, host string) (string, error) {
+	ctx := context.Background()
+	db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+	if err != nil {
+		return "", err
+	}
+
+	var createUserStmt string
+	err = db.QueryRow(fmt.Sprintf("SHOW CREATE USER %s", formatUserIdentifier(user, host))).Scan(&createUserStmt)
+	return createUserStmt, err
+}
+
+const testAccUserConfig_passwordPolicy = `
+resource "mysql_user" "test" {
+    user                     = "policy_user"
+    host                     = "%"
+    plaintext_password       = "password"
+    password_expire          = "90"
+    password_history         = 5
+    password_reuse_interval  = 365
+    password_require_current = "REQUIRED"
+    failed_login_attempts    = 3
+    password_lock_time       = "2"
+}
+`
+
+const testAccUserConfig_passwordPolicyUpdated = `
+resource "mysql_user" "test" {
+    user                     = "policy_user"
+    host                     = "%"
+    plaintext_password       = "password"
+    password_expire          = "NEVER"
+    password_reuse_interval  = 365
+    password_require_current = "REQUIRED"
+    failed_login_attempts    = 3
+    password_lock_time       = "UNBOUNDED"
+}
+`
+
+const testAccUserConfig_passwordPolicyZero = `
+resource "mysql_user" "test" {
+    user                     = "policy_user"
+    host                     = "%"
+    plaintext_password       = "password"
+    password_expire          = "NEVER"
+    password_history         = 0
+    password_reuse_interval  = 0
+    password_require_current = "REQUIRED"
+    failed_login_attempts    = 0
+    password_lock_time       = "UNBOUNDED"
+}
+`
@@ -0,0 +1,353 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceView() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: CreateView,
+		UpdateContext: UpdateView,
+		ReadContext:   ReadView,
+		DeleteContext: DeleteView,
+		Importer: &schema.ResourceImporter{
+			StateContext: ImportView,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"database": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+
+			"definition": {
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: suppressViewDefinitionDiff,
+			},
+
+			"server_definition": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+
+			"algorithm": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "UNDEFINED",
+				ValidateFunc:     validation.StringInSlice([]string{"UNDEFINED", "MERGE", "TEMPTABLE"}, true),
+				DiffSuppressFunc: suppressCaseDiff,
+			},
+
+			"sql_security": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "DEFINER",
+				ValidateFunc:     validation.StringInSlice([]string{"DEFINER", "INVOKER"}, true),
+				DiffSuppressFunc: suppressCaseDiff,
+			},
+
+			"definer": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ValidateFunc:     validateDefiner,
+				DiffSuppressFunc: suppressDefinerDiff,
+			},
+
+			"check_option": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "NONE",
+				ValidateFunc:     validation.StringInSlice([]string{"NONE", "CASCADED", "LOCAL"}, true),
+				DiffSuppressFunc: suppressCaseDiff,
+			},
+		},
+	}
+}
+
+type viewDefinition struct {
+	Database    string
+	Name        string
+	Definition  string
+	Algorithm   string
+	SQLSecurity string
+	Definer     string
+	CheckOption string
+}
+
+func CreateView(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	view := expandView(d)
+	stmtSQL := createViewSQL(view)
+	logSQL(ctx, stmtSQL)
+
+	_, err = db.ExecContext(ctx, stmtSQL)
+	if err != nil {
+		return diag.Errorf("failed creating view: %v", err)
+	}
+
+	d.SetId(databaseObjectID(view.Database, view.Name))
+
+	if err := setServerDefinition(ctx, d, db); err != nil {
+		return diag.Errorf("failed reading view %s: %v", d.Id(), err)
+	}
+
+	return ReadView(ctx, d, meta)
+}
+
+func UpdateView(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	stmtSQL := createViewSQL(expandView(d))
+	logSQL(ctx, stmtSQL)
+
+	_, err = db.ExecContext(ctx, stmtSQL)
+	if err != nil {
+		return diag.Errorf("failed replacing view: %v", err)
+	}
+
+	if err := setServerDefinition(ctx, d, db); err != nil {
+		return diag.Errorf("failed reading view %s: %v", d.Id(), err)
+	}
+
+	return ReadView(ctx, d, meta)
+}
+
+func ReadView(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	database, name, err := parseDatabaseObjectID(d.Id(), "view")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	view, err := readView(ctx, db, database, name)
+	if err != nil {
+		return diag.Errorf("failed reading view %s: %v", d.Id(), err)
+	}
+	if view == nil {
+		log.Printf("[WARN] View (%s) not found; removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	// The server rewrites definitions, so the configured one is kept unless
+	// the server's changed since it was last applied, or wasn't known yet.
+	if view.Definition != d.Get("server_definition").(string) {
+		d.Set("definition", view.Definition)
+	}
+
+	d.Set("database", view.Database)
+	d.Set("name", view.Name)
+	d.Set("server_definition", view.Definition)
+	d.Set("algorithm", view.Algorithm)
+	d.Set("sql_security", view.SQLSecurity)
+	d.Set("definer", view.Definer)
+	d.Set("check_option", view.CheckOption)
+
+	return nil
+}
+
+func DeleteView(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	stmtSQL := fmt.Sprintf("DROP VIEW %s.%s", quoteIdentifier(d.Get("database").(string)), quoteIdentifier(d.Get("name").(string)))
+	logSQL(ctx, stmtSQL)
+
+	_, err = db.ExecContext(ctx, stmtSQL)
+	if err != nil {
+		return diag.Errorf("failed dropping view: %v", err)
+	}
+
+	return nil
+}
+
+func ImportView(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	database, name, err := parseDatabaseObjectID(d.Id(), "view")
+	if err != nil {
+		return nil, err
+	}
+	d.Set("database", database)
+	d.Set("name", name)
+
+	diags := ReadView(ctx, d, meta)
+	if diags.HasError() {
+		return nil, fmt.Errorf("failed reading view: %v", diags)
+	}
+	if d.Id() == "" {
+		return nil, fmt.Errorf("view %s.%s not found", database, name)
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// setServerDefinition records the definition as the server stores it
+// right after it was applied.
+func setServerDefinition(ctx context.Context, d *schema.ResourceData, db *StatementExecutor) error {
+	database, name, err := parseDatabaseObjectID(d.Id(), "view")
+	if err != nil {
+		return err
+	}
+	view, err := readView(ctx, db, database, name)
+	if err != nil {
+		return err
+	}
+	if view != nil {
+		d.Set("server_definition", view.Definition)
+	}
+	return nil
+}
+
+func expandView(d *schema.ResourceData) *viewDefinition {
+	return &viewDefinition{
+		Database:    d.Get("database").(string),
+		Name:        d.Get("name").(string),
+		Definition:  d.Get("definition").(string),
+		Algorithm:   strings.ToUpper(d.Get("algorithm").(string)),
+		SQLSecurity: strings.ToUpper(d.Get("sql_security").(string)),
+		Definer:     d.Get("definer").(string),
+		CheckOption: strings.ToUpper(d.Get("check_option").(string)),
+	}
+}
+
+func createViewSQL(view *viewDefinition) string {
+	stmtSQL := "CREATE OR REPLACE"
+	if view.Algorithm != "" {
+		stmtSQL += " ALGORITHM = " + view.Algorithm
+	}
+	if view.Definer != "" {
+		stmtSQL += " DEFINER = " + formatDefiner(view.Definer)
+	}
+	if view.SQLSecurity != "" {
+		stmtSQL += " SQL SECURITY " + view.SQLSecurity
+	}
+	stmtSQL += fmt.Sprintf(" VIEW %s.%s AS %s", quoteIdentifier(view.Database), quoteIdentifier(view.Name), strings.TrimRight(strings.TrimSpace(view.Definition), ";"))
+	if view.CheckOption != "" && view.CheckOption != "NONE" {
+		stmtSQL += fmt.Sprintf(" WITH %s CHECK OPTION", view.CheckOption)
+	}
+	return stmtSQL
+}
+
+// showCreateViewAlgorithmRegex finds the algorithm in SHOW CREATE VIEW, as
+// only MariaDB lists it in information_schema.VIEWS.
+var showCreateViewAlgorithmRegex = regexp.MustCompile(`(?i)\bALGORITHM\s*=\s*(\w+)`)
+
+// readView reads the view from information_schema, or returns nil if it
+// doesn't exist.
+func readView(ctx context.Context, db *StatementExecutor, database, name string) (*viewDefinition, error) {
+	view := &viewDefinition{Database: database, Name: name}
+
+	algorithm := "''"
+	if db.Server.Flavor == FlavorMariaDB {
+		algorithm = "ALGORITHM"
+	}
+	stmtSQL := fmt.Sprintf(`SELECT VIEW_DEFINITION, CHECK_OPTION, DEFINER, SECURITY_TYPE, %s
+FROM information_schema.VIEWS
+WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?`, algorithm)
+	logSQL(ctx, stmtSQL)
+
+	err := db.QueryRowContext(ctx, stmtSQL, database, name).Scan(&view.Definition, &view.CheckOption, &view.Definer, &view.SQLSecurity, &view.Algorithm)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if view.Algorithm == "" {
+		stmtSQL = fmt.Sprintf("SHOW CREATE VIEW %s.%s", quoteIdentifier(database), quoteIdentifier(name))
+		logSQL(ctx, stmtSQL)
+
+		var viewName, createView, characterSetClient, collationConnection string
+		err := db.QueryRowContext(ctx, stmtSQL).Scan(&viewName, &createView, &characterSetClient, &collationConnection)
+		if err != nil {
+			return nil, err
+		}
+		view.Algorithm = "UNDEFINED"
+		if m := showCreateViewAlgorithmRegex.FindStringSubmatch(createView); m != nil {
+			view.Algorithm = strings.ToUpper(m[1])
+		}
+	}
+
+	return view, nil
+}
+
+// formatDefiner quotes a definer given as user@host. The host starts after
+// the last @, as user names may contain one.
+func formatDefiner(definer string) string {
+	definer = strings.NewReplacer("`", "", "'", "").Replace(definer)
+	i := strings.LastIndex(definer, "@")
+	if i < 0 {
+		return formatUserIdentifier(definer, "%")
+	}
+	return formatUserIdentifier(definer[:i], definer[i+1:])
+}
+
+func validateDefiner(v interface{}, k string) ([]string, []error) {
+	definer := v.(string)
+	if definer != "" && !strings.Contains(definer, "@") {
+		return nil, []error{fmt.Errorf("%s must be given as user@host, got %q", k, definer)}
+	}
+	return nil, nil
+}
+
+func suppressDefinerDiff(k, old, new string, d *schema.ResourceData) bool {
+	return formatDefiner(old) == formatDefiner(new)
+}
+
+// normalizeViewDefinition makes view definitions which only differ in
+// whitespace, quoting, the case of keywords and identifiers or in qualifying
+// names with the view's own database compare equal. Literals and
+// parentheses are kept, as they can change the result of the view.
+func normalizeViewDefinition(definition, database string) string {
+	tokens := sqlTokens(definition)
+	for len(tokens) > 0 && tokens[len(tokens)-1] == ";" {
+		tokens = tokens[:len(tokens)-1]
+	}
+	database = strings.ToLower(database)
+	normalized := make([]string, 0, len(tokens))
+	for i := 0; i < len(tokens); i++ {
+		if tokens[i] == database && i+2 < len(tokens) && tokens[i+1] == "." && (i == 0 || tokens[i-1] != ".") {
+			i++
+			continue
+		}
+		normalized = append(normalized, tokens[i])
+	}
+	return strings.Join(normalized, " ")
+}
+
+func suppressViewDefinitionDiff(k, old, new string, d *schema.ResourceData) bool {
+	database := d.Get("database").(string)
+	return normalizeViewDefinition(old, database) == normalizeViewDefinition(new, database)
+}
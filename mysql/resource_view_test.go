@@ -0,0 +1,178 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccView_basic(t *testing.T) {
+	dbName := "tf_view_test"
+	resourceName := "mysql_view.open_orders"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		ProviderFactories: testAccProviderFactories,
+		CheckDestroy:      testAccViewCheckDestroy(dbName, "open_orders"),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccViewConfig(dbName, "SELECT id, status FROM orders WHERE status <> 'cancelled'", "NONE"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccViewExists(dbName, "open_orders"),
+					resource.TestCheckResourceAttr(resourceName, "id", dbName+".open_orders"),
+					resource.TestCheckResourceAttr(resourceName, "algorithm", "UNDEFINED"),
+					resource.TestCheckResourceAttr(resourceName, "sql_security", "DEFINER"),
+					resource.TestCheckResourceAttr(resourceName, "check_option", "NONE"),
+					resource.TestCheckResourceAttrSet(resourceName, "definer"),
+				),
+			},
+			{
+				Config: testAccViewConfig(dbName, "SELECT id, status FROM orders WHERE status = 'new'", "CASCADED"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccViewExists(dbName, "open_orders"),
+					resource.TestCheckResourceAttr(resourceName, "check_option", "CASCADED"),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateVerify: true,
+				ImportStateId:     dbName + ".open_orders",
+				// Imports take the definition as the server rewrote it.
+				ImportStateVerifyIgnore: []string{"definition"},
+			},
+		},
+	})
+}
+
+func TestNormalizeViewDefinition(t *testing.T) {
+	equivalent := map[string][2]string{
+		"formatting": {
+			"SELECT id, status\nFROM orders\nWHERE status <> 'cancelled';",
+			"select `id`,`status` from `orders` where status <> 'cancelled'",
+		},
+		"own database": {
+			"SELECT orders.id FROM orders",
+			"select `shop`.`orders`.`id` from `shop`.`orders`",
+		},
+		"introducer": {
+			"SELECT id FROM orders WHERE status = 'new'",
+			"select id from orders where status = _utf8mb4'new'",
+		},
+	}
+	for name, tc := range equivalent {
+		t.Run(name, func(t *testing.T) {
+			configured, stored := normalizeViewDefinition(tc[0], "shop"), normalizeViewDefinition(tc[1], "shop")
+			if configured != stored {
+				t.Errorf("expected %q, got %q", configured, stored)
+			}
+		})
+	}
+
+	different := map[string][2]string{
+		"column":   {"SELECT id FROM orders", "SELECT status FROM orders"},
+		"literal":  {"SELECT id FROM orders WHERE status = 'New'", "SELECT id FROM orders WHERE status = 'new'"},
+		"database": {"SELECT id FROM orders", "SELECT id FROM archive.orders"},
+		"grouping": {"SELECT id FROM orders WHERE a OR b AND c", "SELECT id FROM orders WHERE (a OR b) AND c"},
+	}
+	for name, tc := range different {
+		t.Run(name, func(t *testing.T) {
+			if normalizeViewDefinition(tc[0], "shop") == normalizeViewDefinition(tc[1], "shop") {
+				t.Errorf("expected %q and %q not to be suppressed", tc[0], tc[1])
+			}
+		})
+	}
+}
+
+func TestCreateViewSQL(t *testing.T) {
+	testCases := map[string]struct {
+		view     viewDefinition
+		expected string
+	}{
+		"defaults": {
+			viewDefinition{Database: "shop", Name: "v", Definition: "SELECT 1;", Algorithm: "UNDEFINED", SQLSecurity: "DEFINER", CheckOption: "NONE"},
+			"CREATE OR REPLACE ALGORITHM = UNDEFINED SQL SECURITY DEFINER VIEW `shop`.`v` AS SELECT 1",
+		},
+		"all": {
+			viewDefinition{Database: "shop", Name: "v", Definition: "SELECT 1", Algorithm: "MERGE", SQLSecurity: "INVOKER", Definer: "app@example@%", CheckOption: "LOCAL"},
+			"CREATE OR REPLACE ALGORITHM = MERGE DEFINER = `app@example`@`%` SQL SECURITY INVOKER VIEW `shop`.`v` AS SELECT 1 WITH LOCAL CHECK OPTION",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if stmt := createViewSQL(&tc.view); stmt != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, stmt)
+			}
+		})
+	}
+}
+
+func testAccViewExists(dbName, viewName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		ctx := context.Background()
+		db, err := connectToMySQL(ctx, testAccProvider.Meta().(*MySQLConfiguration))
+		if err != nil {
+			return err
+		}
+
+		var count int
+		err = db.QueryRow("SELECT COUNT(*) FROM information_schema.VIEWS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ?", dbName, viewName).Scan(&count)
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return fmt.Errorf("view %s.%s not found", dbName, viewName)
+		}
+		return nil
+	}
+}
+
+func testAccViewCheckDestroy(dbName, viewName string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if err := testAccViewExists(dbName, viewName)(s); err == nil {
+			return fmt.Errorf("view %s.%s still exists", dbName, viewName)
+		}
+		return nil
+	}
+}
+
+func testAccViewConfig(dbName, definition, checkOption string) string {
+	return fmt.Sprintf(`
+resource "mysql_database" "test" {
+  name = "%s"
+}
+
+resource "mysql_table" "orders" {
+  database = mysql_database.test.name
+  name     = "orders"
+
+  column {
+    name     = "id"
+    type     = "int"
+    nullable = false
+  }
+
+  column {
+    name = "status"
+    type = "varchar(16)"
+  }
+
+  primary_key = ["id"]
+}
+
+resource "mysql_view" "open_orders" {
+  database     = mysql_database.test.name
+  name         = "open_orders"
+  definition   = "%s"
+  check_option = "%s"
+
+  depends_on = [mysql_table.orders]
+}
+`, dbName, definition, checkOption)
+}
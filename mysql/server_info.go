@@ -0,0 +1,219 @@
+package mysql
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+)
+
+// ServerFlavor is the database engine the provider is connected to.
+type ServerFlavor string
+
+const (
+	FlavorMySQL   ServerFlavor = "mysql"
+	FlavorPercona ServerFlavor = "percona"
+	FlavorMariaDB ServerFlavor = "mariadb"
+	FlavorTiDB    ServerFlavor = "tidb"
+)
+
+// ServerPlatform is the managed service hosting the server, if any.
+type ServerPlatform string
+
+const (
+	PlatformSelfManaged ServerPlatform = ""
+	PlatformAurora      ServerPlatform = "aurora"
+	PlatformRDS         ServerPlatform = "rds"
+	PlatformCloudSQL    ServerPlatform = "cloudsql"
+	PlatformAzure       ServerPlatform = "azure"
+)
+
+// ServerCapabilities tells which features resources may use on the server.
+type ServerCapabilities struct {
+	// Roles are supported by MySQL 8.0+ and MariaDB.
+	Roles bool
+	// DefaultRoles can be set for users.
+	DefaultRoles bool
+	// DualPasswords allow RETAIN CURRENT PASSWORD and DISCARD OLD PASSWORD.
+	DualPasswords bool
+	// AlterUser supports ALTER USER ... IDENTIFIED BY as well as CREATE USER
+	// and ALTER USER ... WITH resource limits. Older servers need SET PASSWORD
+	// and GRANT USAGE ... WITH.
+	AlterUser bool
+	// UserTLSOptions allow setting REQUIRE on users.
+	UserTLSOptions bool
+	// ShowCreateUser is supported to read users.
+	ShowCreateUser bool
+	// MaxUserConnections can be set as a user resource limit.
+	MaxUserConnections bool
+	// MaxStatementTime can be set as a user resource limit.
+	MaxStatementTime bool
+	// ReadablePasswords are stored as PASSWORD() hashes in mysql.user.
+	ReadablePasswords bool
+	// GeneratedColumns are listed in information_schema.COLUMNS with their
+	// expression.
+	GeneratedColumns bool
+	// CheckConstraints are enforced and listed in
+	// information_schema.CHECK_CONSTRAINTS.
+	CheckConstraints bool
+	// InvisibleIndexes can be hidden from the optimizer.
+	InvisibleIndexes bool
+	// StoredPrograms, like procedures, functions, triggers and events, can be
+	// created.
+	StoredPrograms bool
+	// Sequences are supported by MariaDB 10.3+.
+	Sequences bool
+	// PasswordExpiration allows PASSWORD EXPIRE DEFAULT, NEVER and INTERVAL
+	// on users.
+	PasswordExpiration bool
+	// PasswordReuse allows PASSWORD HISTORY and PASSWORD REUSE INTERVAL.
+	PasswordReuse bool
+	// PasswordRequireCurrent allows PASSWORD REQUIRE CURRENT.
+	PasswordRequireCurrent bool
+	// FailedLoginTracking allows FAILED_LOGIN_ATTEMPTS and
+	// PASSWORD_LOCK_TIME.
+	FailedLoginTracking bool
+}
+
+// ServerInfo describes the server of a connection. It's detected once per
+// connection, so resources don't query the version again and again.
+type ServerInfo struct {
+	Flavor   ServerFlavor
+	Platform ServerPlatform
+	// VersionString is the unparsed @@GLOBAL.version.
+	VersionString string
+	Version       *version.Version
+	// TiDBVersion is the version of TiDB, as Version is the version of MySQL
+	// it's compatible with.
+	TiDBVersion string
+
+	Capabilities ServerCapabilities
+}
+
+// azureHostSuffix is part of the host names of Azure Database for MySQL
+// servers in every Azure cloud.
+const azureHostSuffix = ".mysql.database."
+
+// detectServerInfo queries the server of db for its flavor and version. addr
+// is the address it's reached at, which is all that tells Azure apart.
+func detectServerInfo(db rowQueryer, addr string) (*ServerInfo, error) {
+	var versionString, versionComment string
+	err := db.QueryRow("SELECT @@GLOBAL.version, @@GLOBAL.version_comment").Scan(&versionString, &versionComment)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting server version: %w", err)
+	}
+
+	info, err := newServerInfo(versionString, versionComment)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case strings.HasSuffix(versionString, "-google"):
+		info.Platform = PlatformCloudSQL
+	case strings.Contains(addr, azureHostSuffix):
+		info.Platform = PlatformAzure
+	default:
+		info.Platform, err = detectAWSPlatform(db)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return info, nil
+}
+
+// detectAWSPlatform tells RDS and Aurora servers from self-managed ones.
+func detectAWSPlatform(db rowQueryer) (ServerPlatform, error) {
+	var datadir string
+	err := db.QueryRow("SELECT @@GLOBAL.datadir").Scan(&datadir)
+	if err != nil {
+		return "", fmt.Errorf("failed getting server data directory: %w", err)
+	}
+	if !strings.Contains(datadir, "rds") {
+		return PlatformSelfManaged, nil
+	}
+
+	// Aurora keeps its data in /rdsdbdata too, but has its own version.
+	var name, auroraVersion string
+	err = db.QueryRow("SHOW GLOBAL VARIABLES LIKE 'aurora_version'").Scan(&name, &auroraVersion)
+	if errors.Is(err, sql.ErrNoRows) {
+		return PlatformRDS, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed getting Aurora version: %w", err)
+	}
+	return PlatformAurora, nil
+}
+
+// newServerInfo parses the version of the server and derives its flavor and
+// capabilities.
+func newServerInfo(versionString, versionComment string) (*ServerInfo, error) {
+	info := &ServerInfo{
+		Flavor:        FlavorMySQL,
+		VersionString: versionString,
+	}
+
+	switch {
+	case strings.Contains(versionString, "TiDB"):
+		info.Flavor = FlavorTiDB
+		if versions := strings.SplitN(versionString, "-", 3); len(versions) == 3 {
+			info.TiDBVersion = versions[2]
+		}
+	case strings.Contains(versionString, "MariaDB"):
+		info.Flavor = FlavorMariaDB
+	case strings.Contains(versionComment, "Percona"):
+		info.Flavor = FlavorPercona
+	}
+
+	var err error
+	info.Version, err = version.NewVersion(strings.SplitN(versionString, ":", 2)[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing server version %q: %w", versionString, err)
+	}
+
+	info.Capabilities = ServerCapabilities{
+		Roles:              info.Version.GreaterThan(mustVersion("8.0.0")),
+		DefaultRoles:       info.Version.GreaterThanOrEqual(mustVersion("8.0.0")),
+		DualPasswords:      info.isMySQL() && info.Version.GreaterThanOrEqual(mustVersion("8.0.14")),
+		AlterUser:          info.Version.GreaterThanOrEqual(mustVersion("5.7.6")),
+		UserTLSOptions:     info.Version.GreaterThan(mustVersion("5.7.0")),
+		ShowCreateUser:     info.Version.GreaterThan(mustVersion("5.7.0")),
+		MaxUserConnections: info.Flavor != FlavorTiDB,
+		MaxStatementTime:   info.Flavor == FlavorMariaDB && info.Version.GreaterThanOrEqual(mustVersion("10.1.1")),
+		ReadablePasswords:  info.Version.LessThan(mustVersion("8.0.0")),
+		StoredPrograms:     info.Flavor != FlavorTiDB,
+	}
+
+	switch info.Flavor {
+	case FlavorMariaDB:
+		info.Capabilities.GeneratedColumns = info.Version.GreaterThanOrEqual(mustVersion("10.2.5"))
+		info.Capabilities.CheckConstraints = info.Version.GreaterThanOrEqual(mustVersion("10.2.1"))
+		info.Capabilities.Sequences = info.Version.GreaterThanOrEqual(mustVersion("10.3.0"))
+		info.Capabilities.PasswordExpiration = info.Version.GreaterThanOrEqual(mustVersion("10.4.3"))
+	case FlavorTiDB:
+		info.Capabilities.GeneratedColumns = true
+		info.Capabilities.InvisibleIndexes = true
+	default:
+		info.Capabilities.GeneratedColumns = info.Version.GreaterThanOrEqual(mustVersion("5.7.6"))
+		info.Capabilities.CheckConstraints = info.Version.GreaterThanOrEqual(mustVersion("8.0.16"))
+		info.Capabilities.InvisibleIndexes = info.Version.GreaterThanOrEqual(mustVersion("8.0.0"))
+		info.Capabilities.PasswordExpiration = info.Version.GreaterThanOrEqual(mustVersion("5.7.6"))
+		info.Capabilities.PasswordReuse = info.Version.GreaterThanOrEqual(mustVersion("8.0.3"))
+		info.Capabilities.PasswordRequireCurrent = info.Version.GreaterThanOrEqual(mustVersion("8.0.13"))
+		info.Capabilities.FailedLoginTracking = info.Version.GreaterThanOrEqual(mustVersion("8.0.19"))
+	}
+
+	return info, nil
+}
+
+// isMySQL tells whether the server runs MySQL itself or a build of it.
+func (info *ServerInfo) isMySQL() bool {
+	return info.Flavor == FlavorMySQL || info.Flavor == FlavorPercona
+}
+
+func mustVersion(v string) *version.Version {
+	return version.Must(version.NewVersion(v))
+}
@@ -0,0 +1,121 @@
+package mysql
+
+import (
+	"testing"
+)
+
+func TestNewServerInfo_Flavor(t *testing.T) {
+	testCases := map[string]struct {
+		versionString  string
+		versionComment string
+		flavor         ServerFlavor
+		version        string
+		tidbVersion    string
+	}{
+		"mysql":          {"8.0.35", "MySQL Community Server - GPL", FlavorMySQL, "8.0.35", ""},
+		"mysql log":      {"5.7.42-log", "MySQL Community Server (GPL)", FlavorMySQL, "5.7.42", ""},
+		"percona":        {"8.0.33-25", "Percona Server (GPL), Release '25', Revision '60c9e2c5'", FlavorPercona, "8.0.33", ""},
+		"mariadb":        {"10.11.6-MariaDB-1:10.11.6+maria~ubu2204", "mariadb.org binary distribution", FlavorMariaDB, "10.11.6", ""},
+		"tidb":           {"8.0.11-TiDB-v7.5.0", "TiDB Server (Apache License 2.0) Community Edition, MySQL 8.0 compatible", FlavorTiDB, "8.0.11", "v7.5.0"},
+		"cloudsql mysql": {"8.0.31-google", "(Google)", FlavorMySQL, "8.0.31", ""},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			info, err := newServerInfo(tc.versionString, tc.versionComment)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if info.Flavor != tc.flavor {
+				t.Errorf("expected flavor %s, got %s", tc.flavor, info.Flavor)
+			}
+			if info.Version.Core().String() != tc.version {
+				t.Errorf("expected version %s, got %s", tc.version, info.Version.Core())
+			}
+			if info.TiDBVersion != tc.tidbVersion {
+				t.Errorf("expected TiDB version %q, got %q", tc.tidbVersion, info.TiDBVersion)
+			}
+		})
+	}
+}
+
+func TestNewServerInfo_Capabilities(t *testing.T) {
+	testCases := map[string]struct {
+		versionString string
+		expected      ServerCapabilities
+	}{
+		"mysql 5.6": {"5.6.51", ServerCapabilities{
+			MaxUserConnections: true,
+			StoredPrograms:     true,
+			ReadablePasswords:  true,
+		}},
+		"mysql 5.7": {"5.7.42", ServerCapabilities{
+			AlterUser:          true,
+			UserTLSOptions:     true,
+			ShowCreateUser:     true,
+			MaxUserConnections: true,
+			StoredPrograms:     true,
+			ReadablePasswords:  true,
+			GeneratedColumns:   true,
+			PasswordExpiration: true,
+		}},
+		"mysql 8.0": {"8.0.35", ServerCapabilities{
+			Roles:                  true,
+			DefaultRoles:           true,
+			DualPasswords:          true,
+			AlterUser:              true,
+			UserTLSOptions:         true,
+			ShowCreateUser:         true,
+			MaxUserConnections:     true,
+			StoredPrograms:         true,
+			GeneratedColumns:       true,
+			CheckConstraints:       true,
+			InvisibleIndexes:       true,
+			PasswordExpiration:     true,
+			PasswordReuse:          true,
+			PasswordRequireCurrent: true,
+			FailedLoginTracking:    true,
+		}},
+		"mariadb 10.11": {"10.11.6-MariaDB", ServerCapabilities{
+			Roles:              true,
+			DefaultRoles:       true,
+			AlterUser:          true,
+			UserTLSOptions:     true,
+			ShowCreateUser:     true,
+			MaxUserConnections: true,
+			StoredPrograms:     true,
+			MaxStatementTime:   true,
+			GeneratedColumns:   true,
+			CheckConstraints:   true,
+			Sequences:          true,
+			PasswordExpiration: true,
+		}},
+		"tidb": {"8.0.11-TiDB-v7.5.0", ServerCapabilities{
+			Roles:            true,
+			DefaultRoles:     true,
+			AlterUser:        true,
+			UserTLSOptions:   true,
+			ShowCreateUser:   true,
+			GeneratedColumns: true,
+			InvisibleIndexes: true,
+		}},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			info, err := newServerInfo(tc.versionString, "")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if info.Capabilities != tc.expected {
+				t.Errorf("expected capabilities %+v, got %+v", tc.expected, info.Capabilities)
+			}
+		})
+	}
+}
+
+func TestNewServerInfo_InvalidVersion(t *testing.T) {
+	if _, err := newServerInfo("not-a-version", ""); err == nil {
+		t.Error("expected error for unparsable version")
+	}
+}
@@ -0,0 +1,326 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const (
+	defaultSSHPort = "22"
+	// sshKeepaliveTimeout is how long the reply to a keepalive request is
+	// waited for before the SSH connection is taken as dropped.
+	sshKeepaliveTimeout = 10 * time.Second
+)
+
+// SSHTunnelConfig describes how to reach the MySQL server through an SSH
+// bastion, optionally hopping through further jump hosts first.
+type SSHTunnelConfig struct {
+	Host                  string
+	User                  string
+	PrivateKey            string
+	PrivateKeyPassphrase  string
+	UseAgent              bool
+	KnownHostsFile        string
+	InsecureIgnoreHostKey bool
+	JumpHosts             []SSHJumpHost
+	Timeout               time.Duration
+}
+
+// SSHJumpHost is a host the tunnel hops through before reaching the bastion.
+type SSHJumpHost struct {
+	Host string
+	User string
+}
+
+type sshHop struct {
+	addr   string
+	config *ssh.ClientConfig
+}
+
+// sshTunnelDialer implements the proxy.Dialer interface by opening
+// direct-tcpip channels on an SSH connection to the bastion. The SSH
+// connection is established lazily and re-established if it drops.
+type sshTunnelDialer struct {
+	hops []sshHop
+	// agentSocket is the SSH agent the hops also authenticate with, if any.
+	// It's only connected to while establishing the SSH connections.
+	agentSocket string
+
+	mu      sync.Mutex
+	clients []*ssh.Client
+}
+
+func newSSHTunnelDialer(conf *SSHTunnelConfig) (*sshTunnelDialer, error) {
+	authMethods, agentSocket, err := sshAuthMethods(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	hostKeyCallback, err := sshHostKeyCallback(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := conf.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+
+	hop := func(host, user string) sshHop {
+		if user == "" {
+			user = conf.User
+		}
+		return sshHop{
+			addr: sshAddress(host),
+			config: &ssh.ClientConfig{
+				User:            user,
+				Auth:            authMethods,
+				HostKeyCallback: hostKeyCallback,
+				Timeout:         timeout,
+			},
+		}
+	}
+
+	dialer := &sshTunnelDialer{agentSocket: agentSocket}
+	for _, jumpHost := range conf.JumpHosts {
+		dialer.hops = append(dialer.hops, hop(jumpHost.Host, jumpHost.User))
+	}
+	dialer.hops = append(dialer.hops, hop(conf.Host, conf.User))
+
+	return dialer, nil
+}
+
+// Dial connects to addr from the bastion host.
+func (d *sshTunnelDialer) Dial(network, addr string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, addr)
+}
+
+// DialContext connects to addr from the bastion host, giving up when ctx is
+// done.
+func (d *sshTunnelDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	client, err := d.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := client.DialContext(ctx, network, addr)
+	if err == nil {
+		return conn, nil
+	}
+	// Errors dialing addr, like the server refusing the connection, don't
+	// mean the SSH connection is broken. Only reconnect if the bastion
+	// dropped it since it was established.
+	if ctx.Err() != nil || sshClientAlive(ctx, client) {
+		return nil, fmt.Errorf("failed dialing %s through SSH tunnel: %w", addr, err)
+	}
+
+	d.close(client)
+	client, err = d.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	conn, err = client.DialContext(ctx, network, addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed dialing %s through SSH tunnel: %w", addr, err)
+	}
+	return conn, nil
+}
+
+// sshClientAlive tells whether the SSH connection of client still works, by
+// sending it a keepalive request.
+func sshClientAlive(ctx context.Context, client *ssh.Client) bool {
+	alive := make(chan bool, 1)
+	go func() {
+		_, _, err := client.SendRequest("keepalive@openssh.com", true, nil)
+		alive <- err == nil
+	}()
+
+	select {
+	case ok := <-alive:
+		return ok
+	case <-ctx.Done():
+		return false
+	case <-time.After(sshKeepaliveTimeout):
+		return false
+	}
+}
+
+func (d *sshTunnelDialer) client(ctx context.Context) (*ssh.Client, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if len(d.clients) > 0 {
+		return d.clients[len(d.clients)-1], nil
+	}
+
+	hops := d.hops
+	if d.agentSocket != "" {
+		agentConn, err := (&net.Dialer{}).DialContext(ctx, "unix", d.agentSocket)
+		if err != nil {
+			return nil, fmt.Errorf("failed connecting to SSH agent: %w", err)
+		}
+		// The agent only signs during the handshakes, so it's disconnected
+		// once all hops are connected.
+		defer agentConn.Close()
+		hops = withSSHAuthMethod(d.hops, ssh.PublicKeysCallback(agent.NewClient(agentConn).Signers))
+	}
+
+	var clients []*ssh.Client
+	for i, hop := range hops {
+		var conn net.Conn
+		var err error
+		if i == 0 {
+			dialer := &net.Dialer{Timeout: hop.config.Timeout}
+			conn, err = dialer.DialContext(ctx, "tcp", hop.addr)
+		} else {
+			conn, err = clients[i-1].DialContext(ctx, "tcp", hop.addr)
+		}
+		var client *ssh.Client
+		if err == nil {
+			client, err = newSSHClient(ctx, conn, hop)
+		}
+		if err != nil {
+			for _, c := range clients {
+				c.Close()
+			}
+			return nil, fmt.Errorf("failed connecting to SSH host %s: %w", hop.addr, err)
+		}
+		clients = append(clients, client)
+	}
+
+	d.clients = clients
+	return clients[len(clients)-1], nil
+}
+
+// withSSHAuthMethod returns copies of hops that also authenticate with method.
+func withSSHAuthMethod(hops []sshHop, method ssh.AuthMethod) []sshHop {
+	withMethod := make([]sshHop, len(hops))
+	for i, hop := range hops {
+		config := *hop.config
+		config.Auth = append(append([]ssh.AuthMethod{}, hop.config.Auth...), method)
+		withMethod[i] = sshHop{addr: hop.addr, config: &config}
+	}
+	return withMethod
+}
+
+// close tears down the SSH connections if client is still the current one.
+func (d *sshTunnelDialer) close(client *ssh.Client) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if len(d.clients) == 0 || d.clients[len(d.clients)-1] != client {
+		return
+	}
+	for i := len(d.clients) - 1; i >= 0; i-- {
+		d.clients[i].Close()
+	}
+	d.clients = nil
+}
+
+// newSSHClient runs the SSH handshake with hop on conn. It's aborted when ctx
+// is done, but once established the client isn't tied to ctx.
+func newSSHClient(ctx context.Context, conn net.Conn, hop sshHop) (*ssh.Client, error) {
+	stop := context.AfterFunc(ctx, func() {
+		conn.Close()
+	})
+	clientConn, chans, reqs, err := ssh.NewClientConn(conn, hop.addr, hop.config)
+	if !stop() {
+		if err == nil {
+			clientConn.Close()
+		}
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return ssh.NewClient(clientConn, chans, reqs), nil
+}
+
+func sshAddress(host string) string {
+	if _, _, err := net.SplitHostPort(host); err == nil {
+		return host
+	}
+	return net.JoinHostPort(host, defaultSSHPort)
+}
+
+// sshAuthMethods returns the auth methods of the private key, and the socket
+// of the SSH agent if it's used too.
+func sshAuthMethods(conf *SSHTunnelConfig) ([]ssh.AuthMethod, string, error) {
+	var methods []ssh.AuthMethod
+
+	if conf.PrivateKey != "" {
+		var pem []byte
+		var err error
+		if strings.HasPrefix(conf.PrivateKey, "-----BEGIN") {
+			pem = []byte(conf.PrivateKey)
+		} else {
+			pem, err = os.ReadFile(expandHome(conf.PrivateKey))
+			if err != nil {
+				return nil, "", fmt.Errorf("failed to read SSH private key: %v", err)
+			}
+		}
+
+		var signer ssh.Signer
+		if conf.PrivateKeyPassphrase != "" {
+			signer, err = ssh.ParsePrivateKeyWithPassphrase(pem, []byte(conf.PrivateKeyPassphrase))
+		} else {
+			signer, err = ssh.ParsePrivateKey(pem)
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to parse SSH private key: %v", err)
+		}
+		methods = append(methods, ssh.PublicKeys(signer))
+	}
+
+	var agentSocket string
+	if conf.UseAgent {
+		agentSocket = os.Getenv("SSH_AUTH_SOCK")
+		if agentSocket == "" {
+			return nil, "", fmt.Errorf("use_agent is set, but SSH_AUTH_SOCK is not")
+		}
+	}
+
+	if len(methods) == 0 && agentSocket == "" {
+		return nil, "", fmt.Errorf("either private_key or use_agent must be set for the SSH tunnel")
+	}
+	return methods, agentSocket, nil
+}
+
+func sshHostKeyCallback(conf *SSHTunnelConfig) (ssh.HostKeyCallback, error) {
+	if conf.InsecureIgnoreHostKey {
+		return ssh.InsecureIgnoreHostKey(), nil
+	}
+
+	knownHostsFile := conf.KnownHostsFile
+	if knownHostsFile == "" {
+		knownHostsFile = "~/.ssh/known_hosts"
+	}
+
+	callback, err := knownhosts.New(expandHome(knownHostsFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load SSH known hosts: %v", err)
+	}
+	return callback, nil
+}
+
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[2:])
+}
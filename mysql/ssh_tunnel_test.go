@@ -0,0 +1,424 @@
+package mysql
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+func newTestSSHSigner(t *testing.T) (ssh.Signer, string) {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed generating key: %v", err)
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatalf("failed creating signer: %v", err)
+	}
+	block, err := ssh.MarshalPrivateKey(key, "")
+	if err != nil {
+		t.Fatalf("failed marshaling key: %v", err)
+	}
+	return signer, string(pem.EncodeToMemory(block))
+}
+
+func listenLocal(t *testing.T) net.Listener {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed listening: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	return listener
+}
+
+// startTestSSHServer starts an SSH server that accepts clientKey and
+// forwards direct-tcpip channels, like a bastion host does.
+func startTestSSHServer(t *testing.T, clientKey ssh.PublicKey) (string, ssh.PublicKey) {
+	t.Helper()
+	hostSigner, _ := newTestSSHSigner(t)
+
+	config := &ssh.ServerConfig{
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if conn.User() == "tunnel" && bytes.Equal(key.Marshal(), clientKey.Marshal()) {
+				return nil, nil
+			}
+			return nil, io.EOF
+		},
+	}
+	config.AddHostKey(hostSigner)
+
+	listener := listenLocal(t)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveTestSSHConn(conn, config)
+		}
+	}()
+
+	return listener.Addr().String(), hostSigner.PublicKey()
+}
+
+func serveTestSSHConn(conn net.Conn, config *ssh.ServerConfig) {
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		conn.Close()
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+
+	for newChannel := range chans {
+		if newChannel.ChannelType() != "direct-tcpip" {
+			newChannel.Reject(ssh.UnknownChannelType, "unsupported channel type")
+			continue
+		}
+
+		var target struct {
+			Host     string
+			Port     uint32
+			OrigHost string
+			OrigPort uint32
+		}
+		if err := ssh.Unmarshal(newChannel.ExtraData(), &target); err != nil {
+			newChannel.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+
+		targetConn, err := net.Dial("tcp", net.JoinHostPort(target.Host, strconv.Itoa(int(target.Port))))
+		if err != nil {
+			newChannel.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+
+		channel, channelReqs, err := newChannel.Accept()
+		if err != nil {
+			targetConn.Close()
+			continue
+		}
+		go ssh.DiscardRequests(channelReqs)
+		go func() {
+			io.Copy(channel, targetConn)
+			channel.Close()
+		}()
+		go func() {
+			io.Copy(targetConn, channel)
+			targetConn.Close()
+		}()
+	}
+}
+
+func startTestEchoServer(t *testing.T) string {
+	t.Helper()
+	listener := listenLocal(t)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				io.Copy(conn, conn)
+				conn.Close()
+			}()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func writeTestKnownHosts(t *testing.T, hosts map[string]ssh.PublicKey) string {
+	t.Helper()
+	var contents string
+	for addr, key := range hosts {
+		contents += knownhosts.Line([]string{addr}, key) + "\n"
+	}
+	path := filepath.Join(t.TempDir(), "known_hosts")
+	if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatalf("failed writing known_hosts: %v", err)
+	}
+	return path
+}
+
+func assertEcho(t *testing.T, conn net.Conn) {
+	t.Helper()
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte("ping")); err != nil {
+		t.Fatalf("failed writing through tunnel: %v", err)
+	}
+	reply := make([]byte, 4)
+	if _, err := io.ReadFull(conn, reply); err != nil {
+		t.Fatalf("failed reading through tunnel: %v", err)
+	}
+	if string(reply) != "ping" {
+		t.Errorf("expected echo of ping, got %q", reply)
+	}
+}
+
+func TestSSHTunnelDialer_Dial(t *testing.T) {
+	clientSigner, clientPEM := newTestSSHSigner(t)
+	bastionAddr, bastionKey := startTestSSHServer(t, clientSigner.PublicKey())
+	echoAddr := startTestEchoServer(t)
+
+	dialer, err := newSSHTunnelDialer(&SSHTunnelConfig{
+		Host:           bastionAddr,
+		User:           "tunnel",
+		PrivateKey:     clientPEM,
+		KnownHostsFile: writeTestKnownHosts(t, map[string]ssh.PublicKey{bastionAddr: bastionKey}),
+	})
+	if err != nil {
+		t.Fatalf("failed creating dialer: %v", err)
+	}
+
+	// Both connections should share a single SSH connection.
+	for i := 0; i < 2; i++ {
+		conn, err := dialer.Dial("tcp", echoAddr)
+		if err != nil {
+			t.Fatalf("failed dialing through tunnel: %v", err)
+		}
+		assertEcho(t, conn)
+	}
+	if len(dialer.clients) != 1 {
+		t.Errorf("expected a single SSH connection, got %d", len(dialer.clients))
+	}
+}
+
+func TestSSHTunnelDialer_JumpHost(t *testing.T) {
+	clientSigner, clientPEM := newTestSSHSigner(t)
+	jumpAddr, jumpKey := startTestSSHServer(t, clientSigner.PublicKey())
+	bastionAddr, bastionKey := startTestSSHServer(t, clientSigner.PublicKey())
+	echoAddr := startTestEchoServer(t)
+
+	dialer, err := newSSHTunnelDialer(&SSHTunnelConfig{
+		Host:       bastionAddr,
+		User:       "tunnel",
+		PrivateKey: clientPEM,
+		KnownHostsFile: writeTestKnownHosts(t, map[string]ssh.PublicKey{
+			jumpAddr:    jumpKey,
+			bastionAddr: bastionKey,
+		}),
+		JumpHosts: []SSHJumpHost{{Host: jumpAddr}},
+	})
+	if err != nil {
+		t.Fatalf("failed creating dialer: %v", err)
+	}
+
+	conn, err := dialer.Dial("tcp", echoAddr)
+	if err != nil {
+		t.Fatalf("failed dialing through jump host: %v", err)
+	}
+	assertEcho(t, conn)
+
+	if len(dialer.clients) != 2 {
+		t.Errorf("expected SSH connections to jump host and bastion, got %d", len(dialer.clients))
+	}
+}
+
+func TestSSHTunnelDialer_RejectsUnknownHostKey(t *testing.T) {
+	clientSigner, clientPEM := newTestSSHSigner(t)
+	bastionAddr, _ := startTestSSHServer(t, clientSigner.PublicKey())
+	otherSigner, _ := newTestSSHSigner(t)
+
+	dialer, err := newSSHTunnelDialer(&SSHTunnelConfig{
+		Host:           bastionAddr,
+		User:           "tunnel",
+		PrivateKey:     clientPEM,
+		KnownHostsFile: writeTestKnownHosts(t, map[string]ssh.PublicKey{bastionAddr: otherSigner.PublicKey()}),
+	})
+	if err != nil {
+		t.Fatalf("failed creating dialer: %v", err)
+	}
+
+	if _, err := dialer.Dial("tcp", startTestEchoServer(t)); err == nil {
+		t.Fatal("expected host key mismatch to fail the dial")
+	}
+}
+
+func TestSSHTunnelDialer_ReconnectsAfterDrop(t *testing.T) {
+	clientSigner, clientPEM := newTestSSHSigner(t)
+	bastionAddr, bastionKey := startTestSSHServer(t, clientSigner.PublicKey())
+	echoAddr := startTestEchoServer(t)
+
+	dialer, err := newSSHTunnelDialer(&SSHTunnelConfig{
+		Host:           bastionAddr,
+		User:           "tunnel",
+		PrivateKey:     clientPEM,
+		KnownHostsFile: writeTestKnownHosts(t, map[string]ssh.PublicKey{bastionAddr: bastionKey}),
+	})
+	if err != nil {
+		t.Fatalf("failed creating dialer: %v", err)
+	}
+
+	conn, err := dialer.Dial("tcp", echoAddr)
+	if err != nil {
+		t.Fatalf("failed dialing through tunnel: %v", err)
+	}
+	assertEcho(t, conn)
+
+	// Simulate the bastion dropping the SSH connection.
+	dialer.clients[0].Close()
+
+	conn, err = dialer.Dial("tcp", echoAddr)
+	if err != nil {
+		t.Fatalf("expected dialer to reconnect, got: %v", err)
+	}
+	assertEcho(t, conn)
+}
+
+func TestSSHTunnelDialer_KeepsConnectionOnDialError(t *testing.T) {
+	clientSigner, clientPEM := newTestSSHSigner(t)
+	bastionAddr, bastionKey := startTestSSHServer(t, clientSigner.PublicKey())
+
+	dialer, err := newSSHTunnelDialer(&SSHTunnelConfig{
+		Host:           bastionAddr,
+		User:           "tunnel",
+		PrivateKey:     clientPEM,
+		KnownHostsFile: writeTestKnownHosts(t, map[string]ssh.PublicKey{bastionAddr: bastionKey}),
+	})
+	if err != nil {
+		t.Fatalf("failed creating dialer: %v", err)
+	}
+
+	// Nothing listens on the address once the listener is closed.
+	listener := listenLocal(t)
+	closedAddr := listener.Addr().String()
+	listener.Close()
+
+	if _, err := dialer.Dial("tcp", closedAddr); err == nil {
+		t.Fatal("expected dialing a closed port to fail")
+	}
+	client := dialer.clients[0]
+	if _, err := dialer.Dial("tcp", closedAddr); err == nil {
+		t.Fatal("expected dialing a closed port to fail")
+	}
+	if len(dialer.clients) != 1 || dialer.clients[0] != client {
+		t.Error("expected the SSH connection to be kept after the target refused the connection")
+	}
+}
+
+func TestSSHTunnelDialer_DialContextCanceled(t *testing.T) {
+	clientSigner, clientPEM := newTestSSHSigner(t)
+	bastionAddr, bastionKey := startTestSSHServer(t, clientSigner.PublicKey())
+
+	dialer, err := newSSHTunnelDialer(&SSHTunnelConfig{
+		Host:           bastionAddr,
+		User:           "tunnel",
+		PrivateKey:     clientPEM,
+		KnownHostsFile: writeTestKnownHosts(t, map[string]ssh.PublicKey{bastionAddr: bastionKey}),
+	})
+	if err != nil {
+		t.Fatalf("failed creating dialer: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := dialer.DialContext(ctx, "tcp", startTestEchoServer(t)); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected canceled dial to fail with context.Canceled, got %v", err)
+	}
+}
+
+func TestSSHTunnelDialer_Agent(t *testing.T) {
+	clientSigner, clientPEM := newTestSSHSigner(t)
+	bastionAddr, bastionKey := startTestSSHServer(t, clientSigner.PublicKey())
+	echoAddr := startTestEchoServer(t)
+
+	key, err := ssh.ParseRawPrivateKey([]byte(clientPEM))
+	if err != nil {
+		t.Fatalf("failed parsing key: %v", err)
+	}
+	keyring := agent.NewKeyring()
+	if err := keyring.Add(agent.AddedKey{PrivateKey: key}); err != nil {
+		t.Fatalf("failed adding key to agent: %v", err)
+	}
+
+	socket := filepath.Join(t.TempDir(), "agent.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("failed listening: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	accepted := make(chan struct{}, 1)
+	closed := make(chan struct{}, 1)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			accepted <- struct{}{}
+			go func() {
+				agent.ServeAgent(keyring, conn)
+				closed <- struct{}{}
+			}()
+		}
+	}()
+	t.Setenv("SSH_AUTH_SOCK", socket)
+
+	dialer, err := newSSHTunnelDialer(&SSHTunnelConfig{
+		Host:           bastionAddr,
+		User:           "tunnel",
+		UseAgent:       true,
+		KnownHostsFile: writeTestKnownHosts(t, map[string]ssh.PublicKey{bastionAddr: bastionKey}),
+	})
+	if err != nil {
+		t.Fatalf("failed creating dialer: %v", err)
+	}
+	select {
+	case <-accepted:
+		t.Fatal("expected the agent not to be connected before dialing")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	conn, err := dialer.Dial("tcp", echoAddr)
+	if err != nil {
+		t.Fatalf("failed dialing through tunnel: %v", err)
+	}
+	assertEcho(t, conn)
+
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Error("expected the agent connection to be closed once the SSH connection is established")
+	}
+}
+
+func TestSSHTunnelDialer_RequiresAuthMethod(t *testing.T) {
+	_, err := newSSHTunnelDialer(&SSHTunnelConfig{
+		Host:                  "bastion.example.com",
+		User:                  "tunnel",
+		InsecureIgnoreHostKey: true,
+	})
+	if err == nil {
+		t.Fatal("expected error when neither private_key nor use_agent is set")
+	}
+}
+
+func TestSSHAddress(t *testing.T) {
+	testCases := map[string]string{
+		"bastion.example.com":      "bastion.example.com:22",
+		"bastion.example.com:2222": "bastion.example.com:2222",
+		"10.0.0.1":                 "10.0.0.1:22",
+	}
+	for host, expected := range testCases {
+		if addr := sshAddress(host); addr != expected {
+			t.Errorf("sshAddress(%q): expected %s, got %s", host, expected, addr)
+		}
+	}
+}
@@ -0,0 +1,663 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// StatementOptions configure how resources run statements.
+type StatementOptions struct {
+	AuditLog *auditLog
+	// DryRun logs statements changing the server instead of executing them.
+	DryRun bool
+	// RetryTimeout is how long statements failing with transient errors are
+	// retried for, 0 disables retries.
+	RetryTimeout time.Duration
+	// RetryBackoff is the delay before the first retry, doubled for every
+	// further one.
+	RetryBackoff time.Duration
+}
+
+const (
+	lockWaitTimeoutErrCode = 1205
+	deadlockErrCode        = 1213
+	lostConnectionErrCode  = 2013
+	// Galera returns this while the node isn't synced with the cluster.
+	wsrepNotReadyErrCode = 1047
+
+	maxStatementRetryBackoff = 30 * time.Second
+	defaultNamedLockTimeout  = 60 * time.Second
+)
+
+// StatementExecutor wraps the connection pool handed to resources, so every
+// statement they run is recorded in the audit log, honours dry runs and is
+// retried after transient errors.
+type StatementExecutor struct {
+	*sql.DB
+	StatementOptions
+
+	// Server is the server the statements are run on.
+	Server *ServerInfo
+	// DiagnosticDb looks up the sessions blocking statements that time out.
+	// Nil for the RDS Data API, whose statements don't run in sessions that
+	// lock_wait_timeout could be set for.
+	DiagnosticDb *sql.DB
+
+	// connection is the provider connection the statements are run on, nil
+	// for executors not created from one.
+	connection *DbConnection
+
+	// operation is the resource operation the statements are run for, nil
+	// outside of resource operations.
+	operation *resourceOperation
+}
+
+// rowQueryer is implemented by both *sql.DB and *StatementExecutor.
+type rowQueryer interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// selectRegex matches the keyword of SELECT statements.
+var selectRegex = regexp.MustCompile(`(?i)^\s*SELECT\b`)
+
+// catalogQueryRegex matches statements reading the system schemas, which the
+// provider reads the current state from.
+var catalogQueryRegex = regexp.MustCompile(`(?i)\b(?:information_schema|performance_schema|mysql)\s*\.`)
+
+// sessionVariableRegex matches statements setting session variables, which
+// only affect how the current state is read and so are run in dry runs.
+var sessionVariableRegex = regexp.MustCompile(`(?i)^\s*SET\s+(?:SESSION\s+|LOCAL\s+|@@SESSION\.|@@LOCAL\.|@@)?(\w+)\s*=`)
+
+func newStatementExecutor(ctx context.Context, db *sql.DB, opts StatementOptions) *StatementExecutor {
+	return &StatementExecutor{
+		DB:               db,
+		StatementOptions: opts,
+		operation:        resourceOperationFromContext(ctx),
+	}
+}
+
+// checkOnce tells whether check is run for the first time on the provider
+// connection. Executors not created from one run every check.
+func (db *StatementExecutor) checkOnce(check string) bool {
+	return db.connection == nil || db.connection.checkOnce(check)
+}
+
+func statementOptionsFromMeta(meta interface{}) StatementOptions {
+	switch conf := meta.(type) {
+	case *MySQLConfiguration:
+		return conf.StatementOptions
+	case *RDSDataAPIConfiguration:
+		return conf.StatementOptions
+	default:
+		return StatementOptions{}
+	}
+}
+
+func (db *StatementExecutor) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if db.DryRun && !isSessionVariableStatement(query) {
+		db.skip(ctx, query)
+		return driver.RowsAffected(0), nil
+	}
+
+	var result sql.Result
+	err := db.retry(ctx, query, func() error {
+		var err error
+		start := time.Now()
+		if db.DiagnosticDb != nil {
+			result, err = db.execWithDeadline(ctx, query, args...)
+		} else {
+			result, err = db.DB.ExecContext(ctx, query, args...)
+		}
+		db.record(newAuditEntry(query, time.Since(start), result, err))
+		return err
+	})
+	if err == nil && result == nil {
+		// The retried statement had already been applied.
+		result = driver.RowsAffected(0)
+	}
+	return result, err
+}
+
+// execWithDeadline runs query with lock_wait_timeout limited to the time
+// left until the deadline of ctx, so the server gives up waiting for metadata
+// locks too. If it times out, the error names the sessions holding the locks.
+func (db *StatementExecutor) execWithDeadline(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	conn, err := db.DB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	session, err := getSessionSettings(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	if session.defaultLockWaitTimeout == 0 {
+		// The server doesn't support lock_wait_timeout, rely on the context.
+		return conn.ExecContext(ctx, query, args...)
+	}
+
+	deadline, hasDeadline := ctx.Deadline()
+	if !hasDeadline {
+		return conn.ExecContext(ctx, query, args...)
+	}
+	if timeout, lower := session.lockWaitTimeoutUntil(deadline); lower {
+		if _, err := conn.ExecContext(ctx, fmt.Sprintf("SET SESSION lock_wait_timeout = %d", timeout)); err != nil {
+			return nil, err
+		}
+		defer session.restoreLockWaitTimeout(ctx, conn)
+	}
+
+	watch := watchMetadataLocks(db.DiagnosticDb, session.connectionID, deadline)
+	result, err := conn.ExecContext(ctx, query, args...)
+	blockers := watch.stop()
+	if mysqlErrorNumber(err) == lockWaitTimeoutErrCode || (err != nil && ctx.Err() != nil) {
+		err = describeMetadataLockBlockers(err, blockers)
+	}
+	return result, err
+}
+
+// sessionSettings are the settings of a pooled connection execWithDeadline
+// needs. They are read once per connection and kept on its sessionConn.
+type sessionSettings struct {
+	connectionID int64
+	// defaultLockWaitTimeout is the lock_wait_timeout the connection was
+	// opened with, including init_statements, 0 if the server doesn't
+	// support it.
+	defaultLockWaitTimeout int64
+}
+
+// lockWaitTimeoutUntil returns the lock_wait_timeout for a statement that
+// must finish by deadline, and whether it's lower than the session's own.
+func (session *sessionSettings) lockWaitTimeoutUntil(deadline time.Time) (int64, bool) {
+	timeout := int64(time.Until(deadline) / time.Second)
+	if timeout < 1 {
+		timeout = 1
+	}
+	return timeout, timeout < session.defaultLockWaitTimeout
+}
+
+// restoreLockWaitTimeout sets lock_wait_timeout of conn back to the session's
+// own, so later transactions and statements on the connection aren't limited
+// by the deadline of an earlier one. If that fails, the connection is
+// discarded instead of being returned to the pool.
+func (session *sessionSettings) restoreLockWaitTimeout(ctx context.Context, conn *sql.Conn) {
+	restoreCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), metadataLockQueryTimeout)
+	defer cancel()
+	_, err := conn.ExecContext(restoreCtx, fmt.Sprintf("SET SESSION lock_wait_timeout = %d", session.defaultLockWaitTimeout))
+	if err != nil {
+		tflog.SubsystemWarn(withLogSubsystem(ctx, logSubsystemSQL), logSubsystemSQL, "Discarding connection after failing to restore lock_wait_timeout", map[string]interface{}{
+			"error": err.Error(),
+		})
+		conn.Raw(func(interface{}) error {
+			return driver.ErrBadConn
+		})
+	}
+}
+
+// getSessionSettings returns the sessionSettings of conn, reading them from
+// the server the first time the connection is used. Connections that aren't
+// a sessionConn are read every time.
+func getSessionSettings(ctx context.Context, conn *sql.Conn) (*sessionSettings, error) {
+	var session *sessionSettings
+	if err := conn.Raw(func(dc interface{}) error {
+		if sc, ok := dc.(*sessionConn); ok {
+			session = sc.settings
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if session != nil {
+		return session, nil
+	}
+
+	session = &sessionSettings{}
+	err := conn.QueryRowContext(ctx, "SELECT CONNECTION_ID(), @@SESSION.lock_wait_timeout").Scan(&session.connectionID, &session.defaultLockWaitTimeout)
+	if err != nil {
+		if mysqlErrorNumber(err) == 0 {
+			return nil, err
+		}
+		session.defaultLockWaitTimeout = 0
+	}
+	err = conn.Raw(func(dc interface{}) error {
+		if sc, ok := dc.(*sessionConn); ok {
+			sc.settings = session
+		}
+		return nil
+	})
+	return session, err
+}
+
+// withMaxExecutionTime limits SELECT statements reading the catalog to the
+// time left until the deadline of ctx, so the server stops running them when
+// the client gives up. Statements given by users, like the queries of
+// mysql_sql, are left as they are. MariaDB doesn't support the optimizer
+// hint, and only limits statements through max_statement_time.
+func (db *StatementExecutor) withMaxExecutionTime(ctx context.Context, query string) string {
+	deadline, ok := ctx.Deadline()
+	if !ok || db.Server == nil || db.Server.Flavor == FlavorMariaDB || !catalogQueryRegex.MatchString(query) {
+		return query
+	}
+	loc := selectRegex.FindStringIndex(query)
+	if loc == nil || strings.Contains(query, "/*+") {
+		return query
+	}
+
+	timeout := time.Until(deadline).Milliseconds()
+	if timeout < 1 {
+		timeout = 1
+	}
+	return fmt.Sprintf("%s /*+ MAX_EXECUTION_TIME(%d) */%s", query[:loc[1]], timeout, query[loc[1]:])
+}
+
+func (db *StatementExecutor) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return db.ExecContext(context.Background(), query, args...)
+}
+
+func (db *StatementExecutor) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	var rows *sql.Rows
+	err := db.retry(ctx, query, func() error {
+		var err error
+		start := time.Now()
+		rows, err = db.DB.QueryContext(ctx, db.withMaxExecutionTime(ctx, query), args...)
+		db.record(newAuditEntry(query, time.Since(start), nil, err))
+		return err
+	})
+	return rows, err
+}
+
+func (db *StatementExecutor) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return db.QueryContext(context.Background(), query, args...)
+}
+
+func (db *StatementExecutor) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	var row *sql.Row
+	db.retry(ctx, query, func() error {
+		start := time.Now()
+		row = db.DB.QueryRowContext(ctx, db.withMaxExecutionTime(ctx, query), args...)
+		db.record(newAuditEntry(query, time.Since(start), nil, row.Err()))
+		return row.Err()
+	})
+	return row
+}
+
+func (db *StatementExecutor) QueryRow(query string, args ...interface{}) *sql.Row {
+	return db.QueryRowContext(context.Background(), query, args...)
+}
+
+// StatementTx is a transaction of a StatementExecutor. Its statements are
+// recorded in the audit log and honour dry runs, but aren't retried, as a
+// transient error rolls back the whole transaction.
+type StatementTx struct {
+	*sql.Tx
+	db *StatementExecutor
+}
+
+func (db *StatementExecutor) BeginTx(ctx context.Context, opts *sql.TxOptions) (*StatementTx, error) {
+	tx, err := db.DB.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &StatementTx{Tx: tx, db: db}, nil
+}
+
+func (tx *StatementTx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if tx.db.DryRun && !isSessionVariableStatement(query) {
+		tx.db.skip(ctx, query)
+		return driver.RowsAffected(0), nil
+	}
+
+	start := time.Now()
+	result, err := tx.Tx.ExecContext(ctx, query, args...)
+	tx.db.record(newAuditEntry(query, time.Since(start), result, err))
+	return result, err
+}
+
+// Commit commits the transaction, or rolls it back in dry runs.
+func (tx *StatementTx) Commit() error {
+	if tx.db.DryRun {
+		return tx.Tx.Rollback()
+	}
+	return tx.Tx.Commit()
+}
+
+// StatementConn is a single connection of a StatementExecutor, for statements
+// that depend on session state like named locks. Like those of StatementTx,
+// its statements are recorded and honour dry runs, but aren't retried.
+type StatementConn struct {
+	*sql.Conn
+	db *StatementExecutor
+}
+
+func (db *StatementExecutor) Conn(ctx context.Context) (*StatementConn, error) {
+	conn, err := db.DB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &StatementConn{Conn: conn, db: db}, nil
+}
+
+func (conn *StatementConn) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if conn.db.DryRun && !isSessionVariableStatement(query) {
+		conn.db.skip(ctx, query)
+		return driver.RowsAffected(0), nil
+	}
+
+	start := time.Now()
+	result, err := conn.Conn.ExecContext(ctx, query, args...)
+	conn.db.record(newAuditEntry(query, time.Since(start), result, err))
+	return result, err
+}
+
+// withNamedLock runs f on a connection holding the named lock name, so
+// statements replacing an object aren't interleaved with those of another
+// apply. It waits for the lock until the deadline of ctx, or
+// defaultNamedLockTimeout without one.
+func (db *StatementExecutor) withNamedLock(ctx context.Context, name string, f func(conn *StatementConn) error) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	timeout := int64(defaultNamedLockTimeout / time.Second)
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = int64(time.Until(deadline) / time.Second)
+		if timeout < 1 {
+			timeout = 1
+		}
+	}
+
+	var acquired sql.NullInt64
+	err = conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", name, timeout).Scan(&acquired)
+	if err != nil {
+		return fmt.Errorf("failed acquiring lock %s: %w", name, err)
+	}
+	if acquired.Int64 != 1 {
+		return fmt.Errorf("timed out waiting %ds for lock %s held by another session", timeout, name)
+	}
+	defer func() {
+		releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), metadataLockQueryTimeout)
+		defer cancel()
+		conn.Conn.ExecContext(releaseCtx, "DO RELEASE_LOCK(?)", name)
+	}()
+
+	return f(conn)
+}
+
+// retry runs f until it doesn't fail with a transient error, backing off
+// exponentially, or until RetryTimeout passes.
+//
+// A statement interrupted by the loss of the connection may have been
+// applied already, so when it's retried, errors saying it was are taken as
+// success.
+func (db *StatementExecutor) retry(ctx context.Context, query string, f func() error) error {
+	deadline := time.Now().Add(db.RetryTimeout)
+	backoff := db.RetryBackoff
+	lostConnection := false
+
+	for attempt := 1; ; attempt++ {
+		err := f()
+		if lostConnection && isAlreadyAppliedError(err) {
+			retryCtx := withLogSubsystem(ctx, logSubsystemSQL)
+			tflog.SubsystemWarn(retryCtx, logSubsystemSQL, "Statement was applied before the connection was lost", map[string]interface{}{
+				"statement": redactSQL(query),
+				"error":     err.Error(),
+			})
+			return nil
+		}
+		if err == nil || !isTransientError(err) || time.Now().Add(backoff).After(deadline) {
+			return err
+		}
+		lostConnection = lostConnection || isConnectionLoss(err)
+		if ctxDeadline, ok := ctx.Deadline(); ok && time.Now().Add(backoff).After(ctxDeadline) {
+			return err
+		}
+
+		retryCtx := withLogSubsystem(ctx, logSubsystemSQL)
+		tflog.SubsystemWarn(retryCtx, logSubsystemSQL, "Retrying statement after transient error", map[string]interface{}{
+			"statement": redactSQL(query),
+			"error":     err.Error(),
+			"attempt":   attempt,
+			"backoff":   backoff.String(),
+		})
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > maxStatementRetryBackoff {
+			backoff = maxStatementRetryBackoff
+		}
+	}
+}
+
+// isTransientError tells whether a statement failing with err may succeed
+// when run again: it was rolled back, or the connection was lost.
+func isTransientError(err error) bool {
+	if isConnectionLoss(err) {
+		return true
+	}
+
+	var mysqlError *mysql.MySQLError
+	if !errors.As(err, &mysqlError) {
+		return false
+	}
+	switch mysqlError.Number {
+	case lockWaitTimeoutErrCode, deadlockErrCode:
+		return true
+	case wsrepNotReadyErrCode:
+		return strings.Contains(mysqlError.Message, "WSREP")
+	}
+	return false
+}
+
+func isConnectionLoss(err error) bool {
+	return errors.Is(err, mysql.ErrInvalidConn) || errors.Is(err, driver.ErrBadConn) || mysqlErrorNumber(err) == lostConnectionErrCode
+}
+
+// alreadyAppliedErrCodes are the errors of statements creating or dropping
+// objects that already were.
+var alreadyAppliedErrCodes = map[uint16]bool{
+	1007:               true, // Can't create database; database exists
+	1008:               true, // Can't drop database; database doesn't exist
+	1050:               true, // Table already exists
+	1051:               true, // Unknown table
+	1060:               true, // Duplicate column name
+	1061:               true, // Duplicate key name
+	1091:               true, // Can't DROP; check that column/key exists
+	1304:               true, // Routine already exists
+	1305:               true, // Routine does not exist
+	1359:               true, // Trigger already exists
+	1360:               true, // Trigger does not exist
+	unknownUserErrCode: true, // Operation CREATE USER or DROP USER failed
+	1537:               true, // Event already exists
+	1539:               true, // Unknown event
+}
+
+func isAlreadyAppliedError(err error) bool {
+	return alreadyAppliedErrCodes[mysqlErrorNumber(err)]
+}
+
+func (db *StatementExecutor) skip(ctx context.Context, query string) {
+	ctx = withLogSubsystem(ctx, logSubsystemSQL)
+	tflog.SubsystemInfo(ctx, logSubsystemSQL, "Dry run, not executing statement", map[string]interface{}{
+		"statement": redactSQL(query),
+	})
+
+	if db.operation != nil {
+		db.operation.skip(query)
+	}
+
+	entry := newAuditEntry(query, 0, nil, nil)
+	entry.DryRun = true
+	db.record(entry)
+}
+
+func (db *StatementExecutor) record(entry auditEntry) {
+	if db.AuditLog == nil {
+		return
+	}
+
+	if db.operation != nil {
+		db.operation.add(entry)
+		return
+	}
+	if err := db.AuditLog.write([]auditEntry{entry}); err != nil {
+		log.Printf("[WARN] Failed writing audit log: %v", err)
+	}
+}
+
+func isSessionVariableStatement(query string) bool {
+	match := sessionVariableRegex.FindStringSubmatch(query)
+	return match != nil && !strings.EqualFold(match[1], "PASSWORD")
+}
+
+// resourceOperation collects the statements run by one resource operation.
+// They are handled once it finishes, when the ID of a created resource is
+// known.
+type resourceOperation struct {
+	resourceType string
+	operation    string
+
+	mu      sync.Mutex
+	entries []auditEntry
+	skipped []string
+}
+
+type resourceOperationKey struct{}
+
+func resourceOperationFromContext(ctx context.Context) *resourceOperation {
+	op, _ := ctx.Value(resourceOperationKey{}).(*resourceOperation)
+	return op
+}
+
+func (op *resourceOperation) add(entry auditEntry) {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	op.entries = append(op.entries, entry)
+}
+
+func (op *resourceOperation) skip(query string) {
+	op.mu.Lock()
+	defer op.mu.Unlock()
+	op.skipped = append(op.skipped, redactSQL(query))
+}
+
+type resourceFunc func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics
+
+// wrapResourceFunc attributes the statements run by f to the resource and
+// reports the statements a dry run didn't execute.
+func wrapResourceFunc(resourceType, operation string, f resourceFunc) resourceFunc {
+	return func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		opts := statementOptionsFromMeta(meta)
+		if opts.AuditLog == nil && !opts.DryRun {
+			return f(ctx, d, meta)
+		}
+
+		if opts.DryRun && operation == "update" {
+			// Keep the previous state, as nothing was changed.
+			d.Partial(true)
+		}
+
+		// Deletes and reads of missing resources clear the ID.
+		id := d.Id()
+		op := &resourceOperation{resourceType: resourceType, operation: operation}
+		diags := f(context.WithValue(ctx, resourceOperationKey{}, op), d, meta)
+		if d.Id() != "" {
+			id = d.Id()
+		}
+
+		op.mu.Lock()
+		entries, skipped := op.entries, op.skipped
+		op.mu.Unlock()
+
+		if opts.AuditLog != nil {
+			for i := range entries {
+				entries[i].ResourceType = resourceType
+				entries[i].ResourceID = id
+				entries[i].Operation = operation
+			}
+			if err := opts.AuditLog.write(entries); err != nil {
+				diags = append(diags, diag.Errorf("failed writing audit log: %v", err)...)
+			}
+		}
+
+		if len(skipped) > 0 {
+			if operation == "create" {
+				// Nothing was created, so don't store the resource in state.
+				d.SetId("")
+			}
+			// Fail the operation, so Terraform doesn't consider the changes
+			// applied and resources depending on them aren't changed.
+			diags = append(diag.Diagnostics{{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("Dry run: %s %s not applied", operation, resourceType),
+				Detail:   fmt.Sprintf("The following statements were not executed:\n\n%s", strings.Join(skipped, ";\n")+";"),
+			}}, diags...)
+		}
+		return diags
+	}
+}
+
+// defaultResourceTimeout is the default of every operation in the timeouts
+// block of resources, the same as without one.
+const defaultResourceTimeout = 20 * time.Minute
+
+// wrapResources wraps the operations of resources with wrapResourceFunc and
+// lets their timeouts be configured.
+func wrapResources(resources map[string]*schema.Resource) map[string]*schema.Resource {
+	for name, r := range resources {
+		if r.Timeouts == nil {
+			r.Timeouts = resourceTimeouts(r)
+		}
+		if r.CreateContext != nil {
+			r.CreateContext = schema.CreateContextFunc(wrapResourceFunc(name, "create", resourceFunc(r.CreateContext)))
+		}
+		if r.ReadContext != nil {
+			r.ReadContext = schema.ReadContextFunc(wrapResourceFunc(name, "read", resourceFunc(r.ReadContext)))
+		}
+		if r.UpdateContext != nil {
+			r.UpdateContext = schema.UpdateContextFunc(wrapResourceFunc(name, "update", resourceFunc(r.UpdateContext)))
+		}
+		if r.DeleteContext != nil {
+			r.DeleteContext = schema.DeleteContextFunc(wrapResourceFunc(name, "delete", resourceFunc(r.DeleteContext)))
+		}
+	}
+	return resources
+}
+
+// resourceTimeouts returns the timeouts of the operations r implements.
+func resourceTimeouts(r *schema.Resource) *schema.ResourceTimeout {
+	timeouts := &schema.ResourceTimeout{}
+	if r.CreateContext != nil {
+		timeouts.Create = schema.DefaultTimeout(defaultResourceTimeout)
+	}
+	if r.ReadContext != nil {
+		timeouts.Read = schema.DefaultTimeout(defaultResourceTimeout)
+	}
+	if r.UpdateContext != nil {
+		timeouts.Update = schema.DefaultTimeout(defaultResourceTimeout)
+	}
+	if r.DeleteContext != nil {
+		timeouts.Delete = schema.DefaultTimeout(defaultResourceTimeout)
+	}
+	return timeouts
+}
@@ -0,0 +1,352 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// newUnreachableDB returns a pool whose statements fail, as nothing listens
+// on port 1.
+func newUnreachableDB(t *testing.T) *sql.DB {
+	t.Helper()
+	connector, err := newConnector(&MySQLConfiguration{
+		Config: &mysql.Config{User: "user", Net: "tcp", Addr: "127.0.0.1:1"},
+	})
+	if err != nil {
+		t.Fatalf("failed creating connector: %v", err)
+	}
+	db := sql.OpenDB(connector)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestStatementExecutor_DryRun(t *testing.T) {
+	ctx := context.Background()
+	db := newStatementExecutor(ctx, newUnreachableDB(t), StatementOptions{DryRun: true})
+
+	if _, err := db.ExecContext(ctx, "GRANT SELECT ON `db`.* TO 'jdoe'@'%'"); err != nil {
+		t.Errorf("expected statement not to be executed in dry run, got: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, "SET print_identified_with_as_hex = ON"); err == nil {
+		t.Error("expected session variables to still be set in dry run")
+	}
+	if err := db.QueryRowContext(ctx, "SHOW GRANTS").Err(); err == nil {
+		t.Error("expected queries to still be executed in dry run")
+	}
+}
+
+func TestWrapResourceFunc_DryRun(t *testing.T) {
+	db := newUnreachableDB(t)
+	create := wrapResourceFunc("mysql_database", "create", func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+		executor := newStatementExecutor(ctx, db, statementOptionsFromMeta(meta))
+		if _, err := executor.ExecContext(ctx, "CREATE DATABASE `test`"); err != nil {
+			return diag.FromErr(err)
+		}
+		d.SetId("test")
+		return nil
+	})
+
+	d := schema.TestResourceDataRaw(t, map[string]*schema.Schema{}, map[string]interface{}{})
+	diags := create(context.Background(), d, &MySQLConfiguration{StatementOptions: StatementOptions{DryRun: true}})
+
+	if !diags.HasError() || !strings.Contains(diags[0].Detail, "CREATE DATABASE `test`;") {
+		t.Errorf("expected dry run to report the skipped statement, got %v", diags)
+	}
+	if d.Id() != "" {
+		t.Errorf("expected dry run not to store the created resource, got ID %s", d.Id())
+	}
+}
+
+func TestIsSessionVariableStatement(t *testing.T) {
+	testCases := map[string]bool{
+		"SET print_identified_with_as_hex = ON":      true,
+		"SET SESSION sql_mode = ''":                  true,
+		"SET @@SESSION.lock_wait_timeout=5":          true,
+		"SET GLOBAL max_connections = 100":           false,
+		"SET PASSWORD FOR 'jdoe'@'%' = 'x'":          false,
+		"SET PASSWORD = 'x'":                         false,
+		"SET DEFAULT ROLE `r` TO 'jdoe'@'%'":         false,
+		"CREATE USER 'jdoe'@'%' IDENTIFIED BY 'x'":   false,
+		"ALTER USER 'jdoe'@'%' ACCOUNT LOCK":         false,
+		"  set  local   innodb_lock_wait_timeout =1": true,
+	}
+	for stmt, expected := range testCases {
+		if got := isSessionVariableStatement(stmt); got != expected {
+			t.Errorf("isSessionVariableStatement(%q): expected %t, got %t", stmt, expected, got)
+		}
+	}
+}
+
+func TestStatementExecutor_Retry(t *testing.T) {
+	db := &StatementExecutor{StatementOptions: StatementOptions{
+		RetryTimeout: time.Second,
+		RetryBackoff: time.Millisecond,
+	}}
+
+	calls := 0
+	err := db.retry(context.Background(), "GRANT SELECT ON *.* TO 'jdoe'@'%'", func() error {
+		calls++
+		if calls < 3 {
+			return &mysql.MySQLError{Number: deadlockErrCode, Message: "Deadlock found when trying to get lock"}
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Errorf("expected statement to succeed on the third attempt, got %v after %d attempts", err, calls)
+	}
+
+	calls = 0
+	err = db.retry(context.Background(), "DROP USER 'jdoe'@'%'", func() error {
+		calls++
+		return &mysql.MySQLError{Number: unknownUserErrCode, Message: "Operation DROP USER failed"}
+	})
+	if err == nil || calls != 1 {
+		t.Errorf("expected permanent error not to be retried, got %v after %d attempts", err, calls)
+	}
+}
+
+func TestStatementExecutor_RetryAfterLostConnection(t *testing.T) {
+	db := &StatementExecutor{StatementOptions: StatementOptions{
+		RetryTimeout: time.Second,
+		RetryBackoff: time.Millisecond,
+	}}
+
+	// The first attempt created the user before the connection was lost.
+	calls := 0
+	err := db.retry(context.Background(), "CREATE USER 'jdoe'@'%'", func() error {
+		calls++
+		if calls == 1 {
+			return mysql.ErrInvalidConn
+		}
+		return &mysql.MySQLError{Number: unknownUserErrCode, Message: "Operation CREATE USER failed"}
+	})
+	if err != nil || calls != 2 {
+		t.Errorf("expected statement applied before the connection was lost to succeed, got %v after %d attempts", err, calls)
+	}
+
+	calls = 0
+	err = db.retry(context.Background(), "CREATE DATABASE shop", func() error {
+		calls++
+		if calls == 1 {
+			return &mysql.MySQLError{Number: deadlockErrCode, Message: "Deadlock found when trying to get lock"}
+		}
+		return &mysql.MySQLError{Number: 1007, Message: "Can't create database 'shop'; database exists"}
+	})
+	if err == nil {
+		t.Error("expected the error to be kept after a rolled back attempt")
+	}
+}
+
+func TestStatementExecutor_RetryTimeout(t *testing.T) {
+	db := &StatementExecutor{StatementOptions: StatementOptions{
+		RetryTimeout: 50 * time.Millisecond,
+		RetryBackoff: 10 * time.Millisecond,
+	}}
+
+	calls := 0
+	err := db.retry(context.Background(), "SELECT 1", func() error {
+		calls++
+		return mysql.ErrInvalidConn
+	})
+	if !errors.Is(err, mysql.ErrInvalidConn) {
+		t.Errorf("expected last error after timeout, got %v", err)
+	}
+	// Backing off 10, 20 and 40ms leaves no time for a fourth attempt.
+	if calls != 3 {
+		t.Errorf("expected 3 attempts within the timeout, got %d", calls)
+	}
+
+	db.RetryTimeout = 0
+	calls = 0
+	db.retry(context.Background(), "SELECT 1", func() error {
+		calls++
+		return mysql.ErrInvalidConn
+	})
+	if calls != 1 {
+		t.Errorf("expected no retries with a zero timeout, got %d attempts", calls)
+	}
+}
+
+func TestIsTransientError(t *testing.T) {
+	testCases := []struct {
+		err       error
+		transient bool
+	}{
+		{&mysql.MySQLError{Number: deadlockErrCode}, true},
+		{&mysql.MySQLError{Number: lockWaitTimeoutErrCode}, true},
+		{&mysql.MySQLError{Number: lostConnectionErrCode}, true},
+		{&mysql.MySQLError{Number: wsrepNotReadyErrCode, Message: "WSREP has not yet prepared node for application use"}, true},
+		{&mysql.MySQLError{Number: wsrepNotReadyErrCode, Message: "Unknown command"}, false},
+		{&mysql.MySQLError{Number: unknownDatabaseErrCode}, false},
+		{fmt.Errorf("failed executing SQL: %w", mysql.ErrInvalidConn), true},
+		{errors.New("dial tcp 127.0.0.1:1: connection refused"), false},
+	}
+	for _, tc := range testCases {
+		if transient := isTransientError(tc.err); transient != tc.transient {
+			t.Errorf("isTransientError(%v): expected %t, got %t", tc.err, tc.transient, transient)
+		}
+	}
+}
+
+func TestWrapResources_Timeouts(t *testing.T) {
+	noop := func(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics { return nil }
+	resources := wrapResources(map[string]*schema.Resource{
+		"mysql_example": {
+			CreateContext: noop,
+			ReadContext:   noop,
+			DeleteContext: noop,
+		},
+	})
+
+	timeouts := resources["mysql_example"].Timeouts
+	if timeouts == nil || timeouts.Create == nil || timeouts.Read == nil || timeouts.Delete == nil {
+		t.Fatalf("expected timeouts for every implemented operation, got %+v", timeouts)
+	}
+	if timeouts.Update != nil {
+		t.Error("expected no update timeout without an update operation")
+	}
+	if *timeouts.Create != defaultResourceTimeout {
+		t.Errorf("expected default timeout of %s, got %s", defaultResourceTimeout, *timeouts.Create)
+	}
+}
+
+var maxExecutionTimeRegex = regexp.MustCompile(`MAX_EXECUTION_TIME\((\d+)\)`)
+
+func TestStatementExecutor_WithMaxExecutionTime(t *testing.T) {
+	mysqlServer, _ := newServerInfo("8.0.35", "")
+	mariaDBServer, _ := newServerInfo("10.11.6-MariaDB", "")
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	testCases := map[string]struct {
+		ctx      context.Context
+		server   *ServerInfo
+		query    string
+		expected string
+	}{
+		"select":        {ctx, mysqlServer, "SELECT 1 FROM information_schema.VIEWS", "SELECT /*+ MAX_EXECUTION_TIME(60000) */ 1 FROM information_schema.VIEWS"},
+		"lowercase":     {ctx, mysqlServer, "  select user FROM mysql.user", "  select /*+ MAX_EXECUTION_TIME(60000) */ user FROM mysql.user"},
+		"user query":    {ctx, mysqlServer, "SELECT id FROM shop.orders", "SELECT id FROM shop.orders"},
+		"show":          {ctx, mysqlServer, "SHOW GRANTS FOR 'jdoe'@'%'", "SHOW GRANTS FOR 'jdoe'@'%'"},
+		"hinted":        {ctx, mysqlServer, "SELECT /*+ BKA(t1) */ 1 FROM mysql.user", "SELECT /*+ BKA(t1) */ 1 FROM mysql.user"},
+		"selected name": {ctx, mysqlServer, "SELECTED", "SELECTED"},
+		"mariadb":       {ctx, mariaDBServer, "SELECT 1 FROM mysql.user", "SELECT 1 FROM mysql.user"},
+		"no deadline":   {context.Background(), mysqlServer, "SELECT 1 FROM mysql.user", "SELECT 1 FROM mysql.user"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			db := &StatementExecutor{Server: tc.server}
+			// The deadline gets closer while the test runs.
+			query := maxExecutionTimeRegex.ReplaceAllStringFunc(db.withMaxExecutionTime(tc.ctx, tc.query), func(hint string) string {
+				if ms, _ := strconv.Atoi(maxExecutionTimeRegex.FindStringSubmatch(hint)[1]); ms > 55000 && ms <= 60000 {
+					return "MAX_EXECUTION_TIME(60000)"
+				}
+				return hint
+			})
+			if query != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, query)
+			}
+		})
+	}
+}
+
+func TestSessionSettings_LockWaitTimeoutUntil(t *testing.T) {
+	testCases := []struct {
+		left     time.Duration
+		expected int64
+		lower    bool
+	}{
+		{left: 20 * time.Minute, expected: 1199, lower: true},
+		{left: 2 * time.Hour, expected: 7199, lower: false},
+		{left: -time.Minute, expected: 1, lower: true},
+	}
+	session := &sessionSettings{defaultLockWaitTimeout: 3600}
+	for _, tc := range testCases {
+		timeout, lower := session.lockWaitTimeoutUntil(time.Now().Add(tc.left))
+		if timeout != tc.expected || lower != tc.lower {
+			t.Errorf("lockWaitTimeoutUntil(%s) with %d set: expected %d, %t, got %d, %t", tc.left, session.defaultLockWaitTimeout, tc.expected, tc.lower, timeout, lower)
+		}
+	}
+}
+
+func TestDescribeMetadataLockBlockers(t *testing.T) {
+	err := &mysql.MySQLError{Number: lockWaitTimeoutErrCode, Message: "Lock wait timeout exceeded; try restarting transaction"}
+	if describeMetadataLockBlockers(err, nil) != err {
+		t.Error("expected error to be unchanged without blockers")
+	}
+
+	blocker := metadataLockBlocker{
+		ID:           sql.NullInt64{Int64: 42, Valid: true},
+		User:         sql.NullString{String: "app", Valid: true},
+		Host:         sql.NullString{String: "10.0.0.5", Valid: true},
+		Command:      sql.NullString{String: "Query", Valid: true},
+		Time:         sql.NullInt64{Int64: 300, Valid: true},
+		Info:         sql.NullString{String: "ALTER USER 'app'@'%' IDENTIFIED BY 'secret'", Valid: true},
+		ObjectType:   sql.NullString{String: "TABLE", Valid: true},
+		ObjectSchema: sql.NullString{String: "app", Valid: true},
+		ObjectName:   sql.NullString{String: "orders", Valid: true},
+		LockType:     sql.NullString{String: "SHARED_READ", Valid: true},
+	}
+	described := describeMetadataLockBlockers(err, []metadataLockBlocker{blocker})
+	if mysqlErrorNumber(described) != lockWaitTimeoutErrCode {
+		t.Error("expected the original error to be wrapped")
+	}
+	expected := "session 42 (app@10.0.0.5) holds a SHARED_READ lock on TABLE `app`.`orders`, Query for 300s: ALTER USER 'app'@'%' IDENTIFIED BY <SENSITIVE>"
+	if !strings.Contains(described.Error(), expected) {
+		t.Errorf("expected error to describe the blocking session, got: %v", described)
+	}
+}
+
+func TestMetadataLockWatch_StoppedEarly(t *testing.T) {
+	watch := watchMetadataLocks(nil, 1, time.Now().Add(time.Hour))
+	if blockers := watch.stop(); blockers != nil {
+		t.Errorf("expected no lookup before the deadline, got %v", blockers)
+	}
+}
+
+func TestStatementExecutor_WithNamedLock(t *testing.T) {
+	ctx := context.Background()
+	testCases := map[string]struct {
+		acquired string
+		ran      bool
+		expected []string
+	}{
+		"acquired": {"1", true, []string{"DROP VIEW `db`.`v`", "DO RELEASE_LOCK(?)"}},
+		"timeout":  {"0", false, nil},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			connector := &fakeConnector{readOnly: tc.acquired}
+			pool := sql.OpenDB(connector)
+			defer pool.Close()
+
+			db := newStatementExecutor(ctx, pool, StatementOptions{})
+			ran := false
+			err := db.withNamedLock(ctx, "terraform:view:db.v", func(conn *StatementConn) error {
+				ran = true
+				_, err := conn.ExecContext(ctx, "DROP VIEW `db`.`v`")
+				return err
+			})
+			if (err == nil) != tc.ran || ran != tc.ran {
+				t.Errorf("expected f to run: %t, got error %v", tc.ran, err)
+			}
+			if !reflect.DeepEqual(connector.executed, tc.expected) {
+				t.Errorf("expected %q, got %q", tc.expected, connector.executed)
+			}
+		})
+	}
+}
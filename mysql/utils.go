@@ -6,7 +6,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"log"
+	"strings"
 	"sync"
 
 	"github.com/go-sql-driver/mysql"
@@ -55,49 +55,50 @@ func hashSum(contents interface{}) string {
 	return fmt.Sprintf("%x", sha256.Sum256([]byte(contents.(string))))
 }
 
-func getDatabaseFromMeta(ctx context.Context, meta interface{}) (*sql.DB, error) {
+func getDatabaseFromMeta(ctx context.Context, meta interface{}) (*StatementExecutor, error) {
 	switch conf := meta.(type) {
 	case *MySQLConfiguration:
 		oneConnection, err := connectToMySQLInternal(ctx, conf)
 		if err != nil {
 			return nil, fmt.Errorf("failed to connect to MySQL: %v", err)
 		}
-		return oneConnection.Db, nil
+		executor := newStatementExecutor(ctx, oneConnection.Db, conf.StatementOptions)
+		executor.Server = oneConnection.ServerInfo
+		executor.DiagnosticDb = oneConnection.DiagnosticDb
+		executor.connection = oneConnection
+		return executor, nil
 
 	case *RDSDataAPIConfiguration:
-		return connectToRDSDataAPI(ctx, conf)
+		oneConnection, err := connectToRDSDataAPIInternal(ctx, conf)
+		if err != nil {
+			return nil, err
+		}
+		executor := newStatementExecutor(ctx, oneConnection.Db, conf.StatementOptions)
+		executor.Server = oneConnection.ServerInfo
+		executor.connection = oneConnection
+		return executor, nil
 
 	default:
 		return nil, fmt.Errorf("unexpected configuration type: %T", meta)
 	}
 }
 
-func getVersionFromMeta(ctx context.Context, meta interface{}) *version.Version {
-	switch conf := meta.(type) {
-	case *MySQLConfiguration:
-		oneConnection, err := connectToMySQLInternal(ctx, conf)
-		if err != nil {
-			log.Panicf("getting DB got us error: %v", err)
-		}
-		return oneConnection.Version
-
-	case *RDSDataAPIConfiguration:
-		db, err := getDatabaseFromMeta(ctx, meta)
-		if err != nil {
-			log.Panicf("getting DB got us error: %v", err)
-		}
-
-		ver, err := serverVersion(db)
-		if err != nil {
-			log.Panicf("getting version got us error: %v", err)
-		}
-
-		return ver
+// getServerInfoFromMeta returns the flavor, version and capabilities of the
+// server the provider is connected to.
+func getServerInfoFromMeta(ctx context.Context, meta interface{}) (*ServerInfo, error) {
+	db, err := getDatabaseFromMeta(ctx, meta)
+	if err != nil {
+		return nil, err
+	}
+	return db.Server, nil
+}
 
-	default:
-		log.Panicf("unexpected configuration type: %T", meta)
-		return nil
+func getVersionFromMeta(ctx context.Context, meta interface{}) (*version.Version, error) {
+	serverInfo, err := getServerInfoFromMeta(ctx, meta)
+	if err != nil {
+		return nil, err
 	}
+	return serverInfo.Version, nil
 }
 
 // 0 == not mysql error or not error at all.
@@ -127,6 +128,35 @@ func cloudsqlErrorNumber(err error) int {
 	return 0
 }
 
+// connectToRDSDataAPIInternal returns the cached Data API connection of conf,
+// connecting and detecting the server the first time.
+func connectToRDSDataAPIInternal(ctx context.Context, conf *RDSDataAPIConfiguration) (*DbConnection, error) {
+	connectionCacheMtx.Lock()
+	defer connectionCacheMtx.Unlock()
+
+	cacheKey := conf.connectionCacheKey()
+	if connectionCache[cacheKey] != nil {
+		return connectionCache[cacheKey], nil
+	}
+
+	db, err := connectToRDSDataAPI(ctx, conf)
+	if err != nil {
+		return nil, err
+	}
+
+	serverInfo, err := detectServerInfo(db, "")
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	connectionCache[cacheKey] = &DbConnection{
+		Db:         db,
+		ServerInfo: serverInfo,
+	}
+	return connectionCache[cacheKey], nil
+}
+
 func connectToRDSDataAPI(ctx context.Context, conf *RDSDataAPIConfiguration) (*sql.DB, error) {
 	rdsConnector := rds.NewConnector(rds.NewDriver(), rdsdata.NewFromConfig(conf.AWSConfig), conf.Config)
 
@@ -140,3 +170,32 @@ func connectToRDSDataAPI(ctx context.Context, conf *RDSDataAPIConfiguration) (*s
 
 	return db, nil
 }
+
+// databaseObjectID returns the ID of resources managing objects of a
+// database, like tables.
+func databaseObjectID(database, name string) string {
+	return fmt.Sprintf("%s.%s", database, name)
+}
+
+// parseDatabaseObjectID splits the ID of a database object, of the given kind,
+// into its database and name.
+func parseDatabaseObjectID(id, kind string) (string, string, error) {
+	database, name, ok := strings.Cut(id, ".")
+	if !ok || database == "" || name == "" {
+		return "", "", fmt.Errorf("wrong ID format %s (expected DATABASE.%s)", id, strings.ToUpper(kind))
+	}
+	return database, name, nil
+}
+
+// maxLockNameLength is the longest name GET_LOCK accepts.
+const maxLockNameLength = 64
+
+// databaseObjectLockName returns the name of the lock taken while replacing a
+// database object. Names too long for GET_LOCK are hashed.
+func databaseObjectLockName(kind, database, name string) string {
+	lockName := fmt.Sprintf("terraform:%s:%s", kind, databaseObjectID(database, name))
+	if len(lockName) > maxLockNameLength {
+		lockName = fmt.Sprintf("terraform:%x", sha256.Sum256([]byte(lockName)))[:maxLockNameLength]
+	}
+	return lockName
+}